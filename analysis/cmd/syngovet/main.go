@@ -0,0 +1,34 @@
+// Copyright 2019-2026 Matthew Wilson and Synesis Information Systems. All
+// rights reserved. Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+ * Created: 19th October 2026
+ * Updated:
+ */
+
+// The syngovet command runs the syngovet analyzer, either standalone:
+//
+//	syngovet ./...
+//
+// or via go vet:
+//
+//	go vet -vettool=$(which syngovet) ./...
+//
+// The analyzer and the command are in a module of their own, so that their
+// dependencies are not imposed on importers of package sync; the command
+// may be installed by:
+//
+//	go install github.com/synesissoftware/syngo/analysis/cmd/syngovet@latest
+package main
+
+import (
+	"github.com/synesissoftware/syngo/analysis/syngovet"
+
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+
+	singlechecker.Main(syngovet.Analyzer)
+}
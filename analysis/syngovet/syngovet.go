@@ -0,0 +1,608 @@
+// Copyright 2019-2026 Matthew Wilson and Synesis Information Systems. All
+// rights reserved. Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+ * Created: 19th October 2026
+ * Updated:
+ */
+
+// Package syngovet defines an Analyzer that reports likely misuse of the
+// types in package github.com/synesissoftware/syngo/sync.
+package syngovet
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"math"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const doc = `check for misuse of syngo sync types
+
+The syngovet analyzer reports:
+
+- copies of BoolLatch, ErrorLatch, DownLatch, UpLatch, Uint64DownLatch,
+  Uint64UpLatch, SealableDownLatch, BitLatch, WideBitLatch, DownCounter,
+  UpCounter and FloatCounter values (or of values that contain them) after
+  first use, since stepping the copy does not affect the original;
+- calls to BoolLatch.Set(), ErrorLatch.Set(), SealableDownLatch.Seal(),
+  the SetBit() methods of the bit latches, or the Step() (or StepN())
+  methods of the numeric latches whose flipped result is discarded, where
+  the latch's state is subsequently checked by Load() or IsLatched(),
+  which is racy: another goroutine may have flipped the latch in between;
+- calls to NewDownLatch(), NewUpLatch(), NewUint64DownLatch(),
+  NewUint64UpLatch() and NewSealableDownLatch() with constant arguments
+  that will panic.`
+
+// The Analyzer that reports misuse of syngo sync types.
+var Analyzer = &analysis.Analyzer{
+	Name:     "syngovet",
+	Doc:      doc,
+	URL:      "https://pkg.go.dev/github.com/synesissoftware/syngo/analysis/syngovet",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+const syngoSyncPath = "github.com/synesissoftware/syngo/sync"
+
+var checkedTypeNames = map[string]bool{
+	"BoolLatch":         true,
+	"ErrorLatch":        true,
+	"DownLatch":         true,
+	"UpLatch":           true,
+	"Uint64DownLatch":   true,
+	"Uint64UpLatch":     true,
+	"SealableDownLatch": true,
+	"BitLatch":          true,
+	"WideBitLatch":      true,
+	"DownCounter":       true,
+	"UpCounter":         true,
+	"FloatCounter":      true,
+}
+
+// Methods whose first result is the flipped indicator.
+var flippingMethodNames = map[string]map[string]bool{
+	"BoolLatch":         {"Set": true},
+	"ErrorLatch":        {"Set": true},
+	"DownLatch":         {"Step": true},
+	"UpLatch":           {"Step": true},
+	"Uint64DownLatch":   {"Step": true, "StepN": true},
+	"Uint64UpLatch":     {"Step": true, "StepN": true},
+	"SealableDownLatch": {"Step": true, "Seal": true},
+	"BitLatch":          {"SetBit": true},
+	"WideBitLatch":      {"SetBit": true},
+}
+
+func run(pass *analysis.Pass) (any, error) {
+
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	firstUses := collectFirstUses(pass, inspect)
+
+	checkCopies(pass, inspect, firstUses)
+	checkDiscardedFlips(pass, inspect)
+	checkConstructorArguments(pass, inspect)
+
+	return nil, nil
+}
+
+// Obtains the name of the checked syngo type that t is, or contains (as a
+// struct field or array element), or the empty string if none.
+func checkedTypeIn(t types.Type) string {
+
+	return checkedTypeInSeen(t, make(map[types.Type]bool))
+}
+
+func checkedTypeInSeen(t types.Type, seen map[types.Type]bool) string {
+
+	if seen[t] {
+
+		return ""
+	}
+
+	seen[t] = true
+
+	if named, ok := types.Unalias(t).(*types.Named); ok {
+
+		obj := named.Obj()
+
+		if obj.Pkg() != nil && obj.Pkg().Path() == syngoSyncPath && checkedTypeNames[obj.Name()] {
+
+			return obj.Name()
+		}
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Struct:
+
+		for i := 0; i != u.NumFields(); i++ {
+
+			if name := checkedTypeInSeen(u.Field(i).Type(), seen); name != "" {
+
+				return name
+			}
+		}
+	case *types.Array:
+
+		return checkedTypeInSeen(u.Elem(), seen)
+	}
+
+	return ""
+}
+
+// Obtains the syngo type name of the receiver of a method call, along with
+// the method name, or empty strings if call is not a call of a method on a
+// checked syngo type.
+func syngoMethodCall(pass *analysis.Pass, call *ast.CallExpr) (recv ast.Expr, typeName, methodName string) {
+
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+
+	if !ok {
+
+		return
+	}
+
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+
+	if !ok {
+
+		return
+	}
+
+	sig := fn.Type().(*types.Signature)
+
+	if sig.Recv() == nil {
+
+		return
+	}
+
+	t := sig.Recv().Type()
+
+	if ptr, ok := t.(*types.Pointer); ok {
+
+		t = ptr.Elem()
+	}
+
+	named, ok := types.Unalias(t).(*types.Named)
+
+	if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != syngoSyncPath {
+
+		return
+	}
+
+	return sel.X, named.Obj().Name(), fn.Name()
+}
+
+// Determines, for each local variable of a checked type, the position at
+// which it is first used - by a method call, or by having its address
+// taken - after which copying it is an error. Parameters are recorded as
+// used from the outset.
+func collectFirstUses(pass *analysis.Pass, inspect *inspector.Inspector) map[types.Object]token.Pos {
+
+	firstUses := make(map[types.Object]token.Pos)
+
+	note := func(id *ast.Ident, pos token.Pos) {
+
+		obj, ok := pass.TypesInfo.Uses[id].(*types.Var)
+
+		if !ok || checkedTypeIn(obj.Type()) == "" {
+
+			return
+		}
+
+		if prev, ok := firstUses[obj]; !ok || pos < prev {
+
+			firstUses[obj] = pos
+		}
+	}
+
+	inspect.Preorder([]ast.Node{(*ast.FuncType)(nil), (*ast.SelectorExpr)(nil), (*ast.UnaryExpr)(nil)}, func(n ast.Node) {
+
+		switch n := n.(type) {
+		case *ast.FuncType:
+
+			for _, fields := range []*ast.FieldList{n.Params, n.Results} {
+
+				if fields == nil {
+
+					continue
+				}
+
+				for _, field := range fields.List {
+
+					for _, name := range field.Names {
+
+						if obj := pass.TypesInfo.Defs[name]; obj != nil {
+
+							firstUses[obj] = token.NoPos
+						}
+					}
+				}
+			}
+		case *ast.SelectorExpr:
+
+			if id, ok := ast.Unparen(n.X).(*ast.Ident); ok {
+
+				if _, isMethod := pass.TypesInfo.Uses[n.Sel].(*types.Func); isMethod {
+
+					note(id, n.Pos())
+				}
+			}
+		case *ast.UnaryExpr:
+
+			if n.Op == token.AND {
+
+				if id, ok := ast.Unparen(n.X).(*ast.Ident); ok {
+
+					note(id, n.Pos())
+				}
+			}
+		}
+	})
+
+	return firstUses
+}
+
+// Indicates whether evaluating x as a value would copy an existing
+// variable (rather than producing a new value).
+func isCopyingExpression(x ast.Expr) bool {
+
+	switch ast.Unparen(x).(type) {
+	case *ast.CallExpr, *ast.CompositeLit, *ast.FuncLit, *ast.BasicLit:
+
+		return false
+	default:
+
+		return true
+	}
+}
+
+// Indicates whether copying x at pos should be reported: local variables
+// are permitted to be copied until first used; everything else (fields,
+// elements, dereferences, parameters, package variables) is assumed to be
+// in use.
+func isCopyAfterUse(pass *analysis.Pass, x ast.Expr, pos token.Pos, firstUses map[types.Object]token.Pos) bool {
+
+	id, ok := ast.Unparen(x).(*ast.Ident)
+
+	if !ok {
+
+		return true
+	}
+
+	obj, ok := pass.TypesInfo.Uses[id].(*types.Var)
+
+	if !ok {
+
+		return false
+	}
+
+	if obj.Parent() == nil || obj.Parent() == obj.Pkg().Scope() {
+
+		return true
+	}
+
+	first, used := firstUses[obj]
+
+	return used && first < pos
+}
+
+func checkCopies(pass *analysis.Pass, inspect *inspector.Inspector, firstUses map[types.Object]token.Pos) {
+
+	check := func(x ast.Expr, what string) {
+
+		if x == nil || !isCopyingExpression(x) {
+
+			return
+		}
+
+		tv, ok := pass.TypesInfo.Types[x]
+
+		if !ok || !tv.IsValue() {
+
+			return
+		}
+
+		name := checkedTypeIn(tv.Type)
+
+		if name == "" {
+
+			return
+		}
+
+		if !isCopyAfterUse(pass, x, x.Pos(), firstUses) {
+
+			return
+		}
+
+		pass.ReportRangef(x, "%s copies %s value containing syngo sync.%s after first use; use a pointer", what, types.ExprString(x), name)
+	}
+
+	nodeFilter := []ast.Node{
+		(*ast.AssignStmt)(nil),
+		(*ast.ValueSpec)(nil),
+		(*ast.CallExpr)(nil),
+		(*ast.ReturnStmt)(nil),
+		(*ast.RangeStmt)(nil),
+		(*ast.CompositeLit)(nil),
+		(*ast.SendStmt)(nil),
+	}
+
+	inspect.Preorder(nodeFilter, func(n ast.Node) {
+
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+
+			if len(n.Lhs) != len(n.Rhs) {
+
+				return
+			}
+
+			for i, rhs := range n.Rhs {
+
+				if id, ok := n.Lhs[i].(*ast.Ident); ok && id.Name == "_" {
+
+					continue
+				}
+
+				check(rhs, "assignment")
+			}
+		case *ast.ValueSpec:
+
+			for _, value := range n.Values {
+
+				check(value, "variable declaration")
+			}
+		case *ast.CallExpr:
+
+			if tv, ok := pass.TypesInfo.Types[n.Fun]; ok && (tv.IsType() || tv.IsBuiltin()) {
+
+				return
+			}
+
+			for _, arg := range n.Args {
+
+				check(arg, "call")
+			}
+		case *ast.ReturnStmt:
+
+			for _, result := range n.Results {
+
+				check(result, "return")
+			}
+		case *ast.RangeStmt:
+
+			if n.Value == nil {
+
+				return
+			}
+
+			if id, ok := n.Value.(*ast.Ident); ok && id.Name == "_" {
+
+				return
+			}
+
+			if tv, ok := pass.TypesInfo.Types[n.Value]; ok {
+
+				if name := checkedTypeIn(tv.Type); name != "" {
+
+					pass.ReportRangef(n.Value, "range variable %s copies value containing syngo sync.%s; iterate by index", types.ExprString(n.Value), name)
+				}
+			} else if obj := pass.TypesInfo.Defs[n.Value.(*ast.Ident)]; obj != nil {
+
+				if name := checkedTypeIn(obj.Type()); name != "" {
+
+					pass.ReportRangef(n.Value, "range variable %s copies value containing syngo sync.%s; iterate by index", types.ExprString(n.Value), name)
+				}
+			}
+		case *ast.CompositeLit:
+
+			for _, elt := range n.Elts {
+
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+
+					elt = kv.Value
+				}
+
+				check(elt, "composite literal")
+			}
+		case *ast.SendStmt:
+
+			check(n.Value, "send")
+		}
+	})
+}
+
+func checkDiscardedFlips(pass *analysis.Pass, inspect *inspector.Inspector) {
+
+	inspect.Preorder([]ast.Node{(*ast.BlockStmt)(nil)}, func(n ast.Node) {
+
+		stmts := n.(*ast.BlockStmt).List
+
+		for i, stmt := range stmts {
+
+			call := discardedFlipCall(stmt)
+
+			if call == nil {
+
+				continue
+			}
+
+			recv, typeName, methodName := syngoMethodCall(pass, call)
+
+			if recv == nil || !flippingMethodNames[typeName][methodName] {
+
+				continue
+			}
+
+			recvText := types.ExprString(recv)
+
+			if check := findStateCheck(pass, stmts[i+1:], recvText); check != "" {
+
+				pass.ReportRangef(call, "flipped result of %s.%s() is discarded but the latch is subsequently checked by %s.%s(); use the flipped result instead", recvText, methodName, recvText, check)
+			}
+		}
+	})
+}
+
+// Obtains the call expression of stmt if it is a statement that discards
+// the first result of a call, either wholly or by assigning it to blank.
+func discardedFlipCall(stmt ast.Stmt) *ast.CallExpr {
+
+	switch stmt := stmt.(type) {
+	case *ast.ExprStmt:
+
+		call, _ := ast.Unparen(stmt.X).(*ast.CallExpr)
+
+		return call
+	case *ast.AssignStmt:
+
+		if len(stmt.Rhs) != 1 {
+
+			return nil
+		}
+
+		if id, ok := stmt.Lhs[0].(*ast.Ident); !ok || id.Name != "_" {
+
+			return nil
+		}
+
+		call, _ := ast.Unparen(stmt.Rhs[0]).(*ast.CallExpr)
+
+		return call
+	}
+
+	return nil
+}
+
+// Searches stmts for a call of Load() or IsLatched() on the receiver whose
+// text is recvText, returning the name of the method if found.
+func findStateCheck(pass *analysis.Pass, stmts []ast.Stmt, recvText string) (methodName string) {
+
+	for _, stmt := range stmts {
+
+		ast.Inspect(stmt, func(n ast.Node) bool {
+
+			if methodName != "" {
+
+				return false
+			}
+
+			call, ok := n.(*ast.CallExpr)
+
+			if !ok {
+
+				return true
+			}
+
+			recv, _, name := syngoMethodCall(pass, call)
+
+			if recv != nil && (name == "Load" || name == "IsLatched") && types.ExprString(recv) == recvText {
+
+				methodName = name
+
+				return false
+			}
+
+			return true
+		})
+
+		if methodName != "" {
+
+			return
+		}
+	}
+
+	return
+}
+
+func checkConstructorArguments(pass *analysis.Pass, inspect *inspector.Inspector) {
+
+	maxLatchDistance := constant.MakeInt64(math.MaxInt64)
+
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+
+		call := n.(*ast.CallExpr)
+
+		fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+
+		if !ok || fn.Pkg() == nil || fn.Pkg().Path() != syngoSyncPath || len(call.Args) != 2 {
+
+			return
+		}
+
+		initial := pass.TypesInfo.Types[call.Args[0]].Value
+		threshold := pass.TypesInfo.Types[call.Args[1]].Value
+
+		if initial == nil || threshold == nil {
+
+			return
+		}
+
+		var distance constant.Value
+
+		switch fn.Name() {
+		case "NewDownLatch":
+
+			if constant.Compare(initial, token.LEQ, threshold) {
+
+				pass.ReportRangef(call, "%s(%s, %s) will panic: initial value must be greater than the threshold", fn.Name(), initial, threshold)
+
+				return
+			}
+
+			distance = constant.BinaryOp(initial, token.SUB, threshold)
+		case "NewUpLatch":
+
+			if constant.Compare(initial, token.GEQ, threshold) {
+
+				pass.ReportRangef(call, "%s(%s, %s) will panic: initial value must be less than the threshold", fn.Name(), initial, threshold)
+
+				return
+			}
+
+			distance = constant.BinaryOp(threshold, token.SUB, initial)
+		case "NewSealableDownLatch":
+
+			if constant.Compare(initial, token.LSS, threshold) {
+
+				pass.ReportRangef(call, "%s(%s, %s) will panic: initial value must not be less than the threshold", fn.Name(), initial, threshold)
+
+				return
+			}
+
+			distance = constant.BinaryOp(constant.BinaryOp(initial, token.SUB, threshold), token.MUL, constant.MakeInt64(2))
+		case "NewUint64DownLatch":
+
+			if constant.Compare(initial, token.LEQ, threshold) {
+
+				pass.ReportRangef(call, "%s(%s, %s) will panic: initial value must be greater than the threshold", fn.Name(), initial, threshold)
+			}
+
+			return
+		case "NewUint64UpLatch":
+
+			if constant.Compare(initial, token.GEQ, threshold) {
+
+				pass.ReportRangef(call, "%s(%s, %s) will panic: initial value must be less than the threshold", fn.Name(), initial, threshold)
+			}
+
+			return
+		default:
+
+			return
+		}
+
+		if constant.Compare(distance, token.GTR, maxLatchDistance) {
+
+			pass.ReportRangef(call, "%s(%s, %s) will panic: latch distance exceeds maximum", fn.Name(), initial, threshold)
+		}
+	})
+}
@@ -0,0 +1,14 @@
+package syngovet_test
+
+import (
+	"github.com/synesissoftware/syngo/analysis/syngovet"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"testing"
+)
+
+func Test_Analyzer(t *testing.T) {
+
+	analysistest.Run(t, analysistest.TestData(), syngovet.Analyzer, "a")
+}
@@ -0,0 +1,180 @@
+package a
+
+import (
+	"github.com/synesissoftware/syngo/sync"
+)
+
+type holder struct {
+	latch   sync.BoolLatch
+	counter sync.UpCounter
+}
+
+var global = sync.NewBoolLatch()
+
+func copies() {
+
+	fresh := sync.NewBoolLatch()
+	copied := fresh // copying before first use is permitted
+
+	copied.Set()
+
+	again := copied // want `assignment copies copied value containing syngo sync.BoolLatch after first use; use a pointer`
+
+	_ = again
+
+	offset := sync.NewUint64DownLatch(10, 0)
+
+	offset.Step()
+
+	offsetCopy := offset // want `assignment copies offset value containing syngo sync.Uint64DownLatch after first use; use a pointer`
+
+	_ = offsetCopy
+
+	counter := sync.NewUpCounter(0)
+
+	counter.Step()
+
+	h := holder{
+		counter: counter, // want `composite literal copies counter value containing syngo sync.UpCounter after first use; use a pointer`
+	}
+
+	byValue(h.latch) // want `call copies h.latch value containing syngo sync.BoolLatch after first use; use a pointer`
+
+	var g = global // want `variable declaration copies global value containing syngo sync.BoolLatch after first use; use a pointer`
+
+	_ = g
+
+	bytes := sync.NewFloatCounter(0)
+
+	bytes.Add(1.5)
+
+	bytesCopy := bytes // want `assignment copies bytes value containing syngo sync.FloatCounter after first use; use a pointer`
+
+	_ = bytesCopy
+
+	latches := make([]sync.DownLatch, 3)
+
+	for _, l := range latches { // want `range variable l copies value containing syngo sync.DownLatch; iterate by index`
+
+		_ = l
+	}
+
+	for i := range latches {
+
+		latches[i].Step()
+	}
+
+	ptr := &h
+
+	h2 := *ptr // want `assignment copies \*ptr value containing syngo sync.BoolLatch after first use; use a pointer`
+
+	_ = h2
+}
+
+func byValue(l sync.BoolLatch) sync.BoolLatch { // ok: declaring the parameter is not itself a copy
+
+	return l // want `return copies l value containing syngo sync.BoolLatch after first use; use a pointer`
+}
+
+func byPointer(l *sync.BoolLatch) *sync.BoolLatch {
+
+	return l
+}
+
+func discardedFlips(ch chan bool) {
+
+	latch := sync.NewBoolLatch()
+
+	latch.Set() // want `flipped result of latch.Set\(\) is discarded but the latch is subsequently checked by latch.Load\(\); use the flipped result instead`
+
+	if latch.Load() {
+
+		ch <- true
+	}
+
+	down := sync.NewDownLatch(2, 0)
+
+	_, _, _ = down.Step() // want `flipped result of down.Step\(\) is discarded but the latch is subsequently checked by down.Load\(\); use the flipped result instead`
+
+	if isLatched, _ := down.Load(); isLatched {
+
+		ch <- true
+	}
+
+	other := sync.NewBoolLatch()
+
+	if other.Set() { // ok: result used
+
+		ch <- other.Load()
+	}
+
+	other.Set() // ok: state not subsequently checked
+
+	offset := sync.NewUint64UpLatch(0, 1<<64-1)
+
+	offset.StepN(4096) // want `flipped result of offset.StepN\(\) is discarded but the latch is subsequently checked by offset.IsLatched\(\); use the flipped result instead`
+
+	if offset.IsLatched() {
+
+		ch <- true
+	}
+
+	work := sync.NewSealableDownLatch(0, 0)
+
+	work.Seal() // want `flipped result of work.Seal\(\) is discarded but the latch is subsequently checked by work.IsLatched\(\); use the flipped result instead`
+
+	if work.IsLatched() {
+
+		ch <- true
+	}
+
+	shutdown := sync.NewErrorLatch()
+
+	shutdown.Set(nil) // want `flipped result of shutdown.Set\(\) is discarded but the latch is subsequently checked by shutdown.IsLatched\(\); use the flipped result instead`
+
+	if shutdown.IsLatched() {
+
+		ch <- true
+	}
+
+	shards := sync.NewBitLatch(8)
+
+	shards.SetBit(3) // want `flipped result of shards.SetBit\(\) is discarded but the latch is subsequently checked by shards.IsLatched\(\); use the flipped result instead`
+
+	if shards.IsLatched() {
+
+		ch <- true
+	}
+
+	participants := sync.NewWideBitLatch(1000)
+
+	if flipped, _ := participants.SetBit(999); flipped { // ok: result used
+
+		ch <- participants.IsLatched()
+	}
+}
+
+func constructors(n int64) {
+
+	_ = sync.NewDownLatch(1, 0)
+	_ = sync.NewDownLatch(0, 0)                      // want `NewDownLatch\(0, 0\) will panic: initial value must be greater than the threshold`
+	_ = sync.NewDownLatch(-1, 0)                     // want `NewDownLatch\(-1, 0\) will panic: initial value must be greater than the threshold`
+	_ = sync.NewDownLatch(0x7FFF_FFFF_FFFF_FFFF, -2) // want `NewDownLatch\(9223372036854775807, -2\) will panic: latch distance exceeds maximum`
+	_ = sync.NewDownLatch(n, 0)
+
+	_ = sync.NewUpLatch(0, 1)
+	_ = sync.NewUpLatch(3, 1)                      // want `NewUpLatch\(3, 1\) will panic: initial value must be less than the threshold`
+	_ = sync.NewUpLatch(-2, 0x7FFF_FFFF_FFFF_FFFF) // want `NewUpLatch\(-2, 9223372036854775807\) will panic: latch distance exceeds maximum`
+	_ = sync.NewUpLatch(0, n)
+
+	_ = sync.NewSealableDownLatch(0, 0)
+	_ = sync.NewSealableDownLatch(0, 1)                      // want `NewSealableDownLatch\(0, 1\) will panic: initial value must not be less than the threshold`
+	_ = sync.NewSealableDownLatch(0x4000_0000_0000_0000, 0)  // want `NewSealableDownLatch\(4611686018427387904, 0\) will panic: latch distance exceeds maximum`
+	_ = sync.NewSealableDownLatch(0x7FFF_FFFF_FFFF_FFFF, -2) // want `NewSealableDownLatch\(9223372036854775807, -2\) will panic: latch distance exceeds maximum`
+
+	_ = sync.NewUint64DownLatch(1<<64-1, 0)
+	_ = sync.NewUint64DownLatch(0, 0) // want `NewUint64DownLatch\(0, 0\) will panic: initial value must be greater than the threshold`
+
+	_ = sync.NewUint64UpLatch(0, 1<<64-1)
+	_ = sync.NewUint64UpLatch(1<<64-1, 1<<64-1) // want `NewUint64UpLatch\(18446744073709551615, 18446744073709551615\) will panic: initial value must be less than the threshold`
+}
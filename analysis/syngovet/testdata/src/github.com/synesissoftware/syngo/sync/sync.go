@@ -0,0 +1,88 @@
+// Package sync is a stub of github.com/synesissoftware/syngo/sync for the
+// analyzer tests.
+package sync
+
+type BoolLatch struct{ value int64 }
+
+func NewBoolLatch() BoolLatch              { return BoolLatch{} }
+func (l *BoolLatch) Set() (flipped bool)   { return }
+func (l *BoolLatch) Load() bool            { return false }
+func (l *BoolLatch) IsLatched() bool       { return false }
+func (l *BoolLatch) Done() <-chan struct{} { return nil }
+
+type ErrorLatch struct{ value int64 }
+
+func NewErrorLatch() ErrorLatch                    { return ErrorLatch{} }
+func (l *ErrorLatch) Set(err error) (flipped bool) { return }
+func (l *ErrorLatch) Err() error                   { return nil }
+func (l *ErrorLatch) IsLatched() bool              { return false }
+
+type DownLatch struct{ value int64 }
+
+func NewDownLatch(initialValue, threshold int64) DownLatch           { return DownLatch{} }
+func (l *DownLatch) Step() (flipped, isLatched bool, newCount int64) { return }
+func (l *DownLatch) Load() (isLatched bool, count int64)             { return }
+
+type UpLatch struct{ value int64 }
+
+func NewUpLatch(initialValue, threshold int64) UpLatch             { return UpLatch{} }
+func (l *UpLatch) Step() (flipped, isLatched bool, newCount int64) { return }
+func (l *UpLatch) Load() (isLatched bool, count int64)             { return }
+
+type Uint64DownLatch struct{ value uint64 }
+
+func NewUint64DownLatch(initialValue, threshold uint64) Uint64DownLatch              { return Uint64DownLatch{} }
+func (l *Uint64DownLatch) Step() (flipped, isLatched bool, newCount uint64)          { return }
+func (l *Uint64DownLatch) StepN(n uint64) (flipped, isLatched bool, newCount uint64) { return }
+func (l *Uint64DownLatch) Load() (isLatched bool, count uint64)                      { return }
+func (l *Uint64DownLatch) IsLatched() bool                                           { return false }
+
+type Uint64UpLatch struct{ value uint64 }
+
+func NewUint64UpLatch(initialValue, threshold uint64) Uint64UpLatch                { return Uint64UpLatch{} }
+func (l *Uint64UpLatch) Step() (flipped, isLatched bool, newCount uint64)          { return }
+func (l *Uint64UpLatch) StepN(n uint64) (flipped, isLatched bool, newCount uint64) { return }
+func (l *Uint64UpLatch) Load() (isLatched bool, count uint64)                      { return }
+func (l *Uint64UpLatch) IsLatched() bool                                           { return false }
+
+type SealableDownLatch struct{ value int64 }
+
+func NewSealableDownLatch(initialValue, threshold int64) SealableDownLatch {
+	return SealableDownLatch{}
+}
+func (l *SealableDownLatch) Add(n int64) error                               { return nil }
+func (l *SealableDownLatch) Seal() (flipped bool)                            { return }
+func (l *SealableDownLatch) Step() (flipped, isLatched bool, newCount int64) { return }
+func (l *SealableDownLatch) Load() (isLatched bool, count int64)             { return }
+func (l *SealableDownLatch) IsLatched() bool                                 { return false }
+
+type BitLatch struct{ value uint64 }
+
+func NewBitLatch(n int) BitLatch                          { return BitLatch{} }
+func (l *BitLatch) SetBit(i int) (flipped, newlySet bool) { return }
+func (l *BitLatch) Load() (isLatched bool, bits uint64)   { return }
+func (l *BitLatch) IsLatched() bool                       { return false }
+
+type WideBitLatch struct{ words []uint64 }
+
+func NewWideBitLatch(n int) *WideBitLatch                     { return &WideBitLatch{} }
+func (l *WideBitLatch) SetBit(i int) (flipped, newlySet bool) { return }
+func (l *WideBitLatch) IsLatched() bool                       { return false }
+
+type DownCounter struct{ value int64 }
+
+func NewDownCounter(initialValue int64) DownCounter { return DownCounter{} }
+func (l *DownCounter) Step() (newCount int64)       { return }
+func (l *DownCounter) Load() (count int64)          { return }
+
+type UpCounter struct{ value int64 }
+
+func NewUpCounter(initialValue int64) UpCounter { return UpCounter{} }
+func (l *UpCounter) Step() (newCount int64)     { return }
+func (l *UpCounter) Load() (count int64)        { return }
+
+type FloatCounter struct{ value uint64 }
+
+func NewFloatCounter(initialValue float64) FloatCounter      { return FloatCounter{} }
+func (c *FloatCounter) Add(delta float64) (newValue float64) { return }
+func (c *FloatCounter) Load() (value float64)                { return }
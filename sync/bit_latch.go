@@ -0,0 +1,389 @@
+// Copyright 2019-2026 Matthew Wilson and Synesis Information Systems. All
+// rights reserved. Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+ * Created: 19th October 2026
+ * Updated:
+ */
+
+// Definition of latches that flip when every bit in a fixed set is set.
+
+package sync
+
+import (
+	"context"
+	"errors"
+	"math/bits"
+	"sync/atomic"
+)
+
+var (
+	errBitLatchSizeOutOfRange = errors.New("bit latch size out of range")
+	errBitLatchBitOutOfRange  = errors.New("bit index out of range")
+)
+
+// Obtains the mask of the low n bits of a word, where 0 < n <= 64.
+func lowBitsMask(n int) uint64 {
+
+	return ^uint64(0) >> (64 - n)
+}
+
+// Appends to missing the indexes, offset by base, of the bits in mask that
+// are not set in word.
+func appendMissingBits(missing []int, base int, word, mask uint64) []int {
+
+	for unset := ^word & mask; unset != 0; unset &= unset - 1 {
+
+		missing = append(missing, base+bits.TrailingZeros64(unset))
+	}
+
+	return missing
+}
+
+// A latch of up to 64 bits, each of which may be set once, that flips when
+// all of them are set, and that may be operated safely by multiple
+// concurrent goroutines.
+//
+// Unlike a DownLatch, the latch records which participants have reported,
+// so that those outstanding may be obtained, and a participant reporting
+// more than once is not counted twice.
+type BitLatch struct {
+	_latchNotifier
+	_observed
+	bits uint64
+	mask uint64
+}
+
+// Creates a new BitLatch of n bits, none of them set.
+//
+// Preconditions:
+// - 0 < n <= 64;
+func NewBitLatch(n int) BitLatch {
+
+	if n <= 0 || n > 64 {
+
+		panic(errBitLatchSizeOutOfRange)
+	}
+
+	observeCreated("BitLatch", 0)
+
+	return BitLatch{
+		mask: lowBitsMask(n),
+	}
+}
+
+// Sets bit i; no effect if it is already set.
+//
+// Returns:
+// flipped - true if this call set the last unset bit, latching the latch;
+// newlySet - true if this call set the bit;
+//
+// Preconditions:
+// - 0 <= i < Len();
+func (l *BitLatch) SetBit(i int) (flipped, newlySet bool) {
+
+	if i < 0 || i >= l.Len() {
+
+		panic(errBitLatchBitOutOfRange)
+	}
+
+	bit := uint64(1) << i
+
+	old := atomic.OrUint64(&l.bits, bit)
+
+	newlySet = old&bit == 0
+	flipped = newlySet && old|bit == l.mask
+
+	if flipped {
+
+		l._latchNotifier.fire()
+	}
+
+	if o := l._observed.load(); o != nil {
+
+		observeStep(o, "BitLatch", l, flipped, old|bit == l.mask, int64(bits.OnesCount64(old|bit)))
+	}
+
+	return
+}
+
+// Indicates whether bit i is set.
+//
+// Preconditions:
+// - 0 <= i < Len();
+func (l *BitLatch) IsSet(i int) bool {
+
+	if i < 0 || i >= l.Len() {
+
+		panic(errBitLatchBitOutOfRange)
+	}
+
+	return atomic.LoadUint64(&l.bits)&(uint64(1)<<i) != 0
+}
+
+// Obtains the current state of the latch, without changing it.
+//
+// Returns:
+// isLatched - true if all bits are set;
+// bits - the bits, bit i of which is set if SetBit(i) has been called;
+func (l *BitLatch) Load() (isLatched bool, bits uint64) {
+
+	bits = atomic.LoadUint64(&l.bits)
+
+	isLatched = bits == l.mask
+
+	return
+}
+
+// Obtains the number of bits of the latch.
+func (l *BitLatch) Len() int {
+
+	return bits.OnesCount64(l.mask)
+}
+
+// Obtains the number of bits that are set.
+func (l *BitLatch) NumSet() int {
+
+	return bits.OnesCount64(atomic.LoadUint64(&l.bits))
+}
+
+// Obtains the indexes, in ascending order, of the bits that are not yet
+// set.
+func (l *BitLatch) Missing() []int {
+
+	return appendMissingBits(nil, 0, atomic.LoadUint64(&l.bits), l.mask)
+}
+
+// Indicates whether the latch has latched, without changing its state.
+func (l *BitLatch) IsLatched() bool {
+
+	return atomic.LoadUint64(&l.bits) == l.mask
+}
+
+// Obtains a channel that is closed when the latch latches.
+func (l *BitLatch) Done() <-chan struct{} {
+
+	return l._latchNotifier.done()
+}
+
+// Blocks the caller until the latch latches.
+func (l *BitLatch) Wait() {
+
+	if o := l._observed.load(); o != nil {
+
+		observeWait(o, "BitLatch", l, &l._latchNotifier, nil)
+
+		return
+	}
+
+	l._latchNotifier.wait()
+}
+
+// Blocks the caller until the latch latches or ctx is done.
+//
+// Returns:
+// nil if the latch latched; ctx.Err() otherwise
+func (l *BitLatch) WaitContext(ctx context.Context) error {
+
+	if o := l._observed.load(); o != nil {
+
+		return observeWait(o, "BitLatch", l, &l._latchNotifier, ctx)
+	}
+
+	return l._latchNotifier.waitContext(ctx)
+}
+
+// Installs o as the observer of the instance, in place of any global
+// observer. Passing nil reverts to the global observer.
+func (l *BitLatch) SetObserver(o Observer) {
+
+	l._observed.set(o)
+}
+
+// Obtains the number of goroutines currently blocked in Wait() or
+// WaitContext().
+func (l *BitLatch) NumWaiters() int {
+
+	return int(l._latchNotifier.numWaiters())
+}
+
+// A latch of any number of bits, each of which may be set once, that flips
+// when all of them are set, and that may be operated safely by multiple
+// concurrent goroutines.
+//
+// The bits are held in an array of words, each set by atomic OR, and the
+// number of bits not yet set is held separately, so that the setting of
+// the last bit is detected without examining the other words.
+type WideBitLatch struct {
+	_latchNotifier
+	_observed
+	words     []uint64
+	n         int
+	remaining int64
+}
+
+// Creates a new WideBitLatch of n bits, none of them set.
+//
+// Preconditions:
+// - n > 0;
+func NewWideBitLatch(n int) *WideBitLatch {
+
+	if n <= 0 {
+
+		panic(errBitLatchSizeOutOfRange)
+	}
+
+	observeCreated("WideBitLatch", 0)
+
+	return &WideBitLatch{
+		words:     make([]uint64, (n+63)/64),
+		n:         n,
+		remaining: int64(n),
+	}
+}
+
+// Sets bit i; no effect if it is already set.
+//
+// Returns:
+// flipped - true if this call set the last unset bit, latching the latch;
+// newlySet - true if this call set the bit;
+//
+// Preconditions:
+// - 0 <= i < Len();
+func (l *WideBitLatch) SetBit(i int) (flipped, newlySet bool) {
+
+	if i < 0 || i >= l.n {
+
+		panic(errBitLatchBitOutOfRange)
+	}
+
+	bit := uint64(1) << (i % 64)
+
+	newlySet = atomic.OrUint64(&l.words[i/64], bit)&bit == 0
+
+	var remaining int64
+
+	if newlySet {
+
+		remaining = atomic.AddInt64(&l.remaining, -1)
+	} else {
+
+		remaining = atomic.LoadInt64(&l.remaining)
+	}
+
+	if newlySet && remaining == 0 {
+
+		flipped = true
+
+		l._latchNotifier.fire()
+	}
+
+	if o := l._observed.load(); o != nil {
+
+		observeStep(o, "WideBitLatch", l, flipped, remaining == 0, int64(l.n)-remaining)
+	}
+
+	return
+}
+
+// Indicates whether bit i is set.
+//
+// Preconditions:
+// - 0 <= i < Len();
+func (l *WideBitLatch) IsSet(i int) bool {
+
+	if i < 0 || i >= l.n {
+
+		panic(errBitLatchBitOutOfRange)
+	}
+
+	return atomic.LoadUint64(&l.words[i/64])&(uint64(1)<<(i%64)) != 0
+}
+
+// Obtains the number of bits of the latch.
+func (l *WideBitLatch) Len() int {
+
+	return l.n
+}
+
+// Obtains the number of bits that are set.
+func (l *WideBitLatch) NumSet() int {
+
+	return l.n - int(atomic.LoadInt64(&l.remaining))
+}
+
+// Obtains the indexes, in ascending order, of the bits that are not yet
+// set.
+//
+// The words are read in turn, so bits set concurrently may or may not be
+// reported.
+func (l *WideBitLatch) Missing() (missing []int) {
+
+	for w := range l.words {
+
+		mask := ^uint64(0)
+
+		if w == len(l.words)-1 {
+
+			mask = lowBitsMask(l.n - w*64)
+		}
+
+		missing = appendMissingBits(missing, w*64, atomic.LoadUint64(&l.words[w]), mask)
+	}
+
+	return
+}
+
+// Indicates whether the latch has latched, without changing its state.
+func (l *WideBitLatch) IsLatched() bool {
+
+	return atomic.LoadInt64(&l.remaining) == 0
+}
+
+// Obtains a channel that is closed when the latch latches.
+func (l *WideBitLatch) Done() <-chan struct{} {
+
+	return l._latchNotifier.done()
+}
+
+// Blocks the caller until the latch latches.
+func (l *WideBitLatch) Wait() {
+
+	if o := l._observed.load(); o != nil {
+
+		observeWait(o, "WideBitLatch", l, &l._latchNotifier, nil)
+
+		return
+	}
+
+	l._latchNotifier.wait()
+}
+
+// Blocks the caller until the latch latches or ctx is done.
+//
+// Returns:
+// nil if the latch latched; ctx.Err() otherwise
+func (l *WideBitLatch) WaitContext(ctx context.Context) error {
+
+	if o := l._observed.load(); o != nil {
+
+		return observeWait(o, "WideBitLatch", l, &l._latchNotifier, ctx)
+	}
+
+	return l._latchNotifier.waitContext(ctx)
+}
+
+// Installs o as the observer of the instance, in place of any global
+// observer. Passing nil reverts to the global observer.
+func (l *WideBitLatch) SetObserver(o Observer) {
+
+	l._observed.set(o)
+}
+
+// Obtains the number of goroutines currently blocked in Wait() or
+// WaitContext().
+func (l *WideBitLatch) NumWaiters() int {
+
+	return int(l._latchNotifier.numWaiters())
+}
@@ -0,0 +1,326 @@
+package sync_test
+
+import (
+	. "github.com/synesissoftware/syngo/sync"
+
+	"github.com/synesissoftware/syngo/sync/synctesting"
+
+	"github.com/stretchr/testify/require"
+
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_BitLatch(t *testing.T) {
+
+	t.Run("NewBitLatch() panics on invalid sizes", func(t *testing.T) {
+
+		for _, n := range []int{-1, 0, 65} {
+
+			require.Panics(t, func() {
+
+				_ = NewBitLatch(n)
+			})
+		}
+	})
+
+	t.Run("SetBit() sets each bit once and flips on the last", func(t *testing.T) {
+
+		latch := NewBitLatch(3)
+
+		require.Equal(t, 3, latch.Len())
+		require.Equal(t, []int{0, 1, 2}, latch.Missing())
+
+		flipped, newlySet := latch.SetBit(1)
+
+		require.False(t, flipped)
+		require.True(t, newlySet)
+		require.True(t, latch.IsSet(1))
+		require.False(t, latch.IsSet(0))
+
+		flipped, newlySet = latch.SetBit(1)
+
+		require.False(t, flipped)
+		require.False(t, newlySet)
+		require.Equal(t, 1, latch.NumSet())
+		require.Equal(t, []int{0, 2}, latch.Missing())
+
+		flipped, _ = latch.SetBit(0)
+
+		require.False(t, flipped)
+
+		flipped, newlySet = latch.SetBit(2)
+
+		require.True(t, flipped)
+		require.True(t, newlySet)
+		require.True(t, latch.IsLatched())
+		require.Empty(t, latch.Missing())
+
+		isLatched, bits := latch.Load()
+
+		require.True(t, isLatched)
+		require.Equal(t, uint64(0b111), bits)
+
+		flipped, newlySet = latch.SetBit(2)
+
+		require.False(t, flipped)
+		require.False(t, newlySet)
+	})
+
+	t.Run("SetBit() and IsSet() out of range panic", func(t *testing.T) {
+
+		latch := NewBitLatch(4)
+
+		for _, i := range []int{-1, 4, 64} {
+
+			require.Panics(t, func() { latch.SetBit(i) })
+			require.Panics(t, func() { _ = latch.IsSet(i) })
+		}
+	})
+
+	t.Run("a 64-bit latch", func(t *testing.T) {
+
+		latch := NewBitLatch(64)
+
+		for i := range 63 {
+
+			flipped, _ := latch.SetBit(i)
+
+			require.False(t, flipped)
+		}
+
+		require.Equal(t, []int{63}, latch.Missing())
+
+		flipped, _ := latch.SetBit(63)
+
+		require.True(t, flipped)
+	})
+
+	t.Run("concurrent shards flip the latch exactly once", func(t *testing.T) {
+
+		synctesting.VerifyNoLeaks(t)
+
+		const numShards = 64
+
+		latch := NewBitLatch(numShards)
+
+		var numFlips int64
+
+		var wg sync.WaitGroup
+
+		for i := range numShards {
+
+			// each shard reports twice, as might a retried participant
+			for range 2 {
+
+				wg.Go(func() {
+
+					if flipped, _ := latch.SetBit(i); flipped {
+
+						atomic.AddInt64(&numFlips, 1)
+					}
+				})
+			}
+		}
+
+		synctesting.RequireFlipsWithin(t, &latch, 5*time.Second)
+
+		wg.Wait()
+
+		require.Equal(t, int64(1), numFlips)
+	})
+
+	t.Run("WaitContext() returns the context's error if not latched", func(t *testing.T) {
+
+		latch := NewBitLatch(2)
+
+		latch.SetBit(0)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		require.ErrorIs(t, latch.WaitContext(ctx), context.DeadlineExceeded)
+		require.Equal(t, []int{1}, latch.Missing())
+	})
+
+	t.Run("per-instance observer", func(t *testing.T) {
+
+		var observer recordingObserver
+
+		latch := NewBitLatch(2)
+
+		latch.SetObserver(&observer)
+
+		latch.SetBit(0)
+		latch.SetBit(0)
+		latch.SetBit(1)
+		latch.SetBit(1)
+		latch.Wait()
+
+		require.Equal(t, []LatchEventKind{LatchStepped, LatchStepped, LatchFlipped, LatchSteppedAfterLatched, LatchWaited}, observer.kinds())
+		require.Equal(t, "BitLatch", observer.events[0].Type)
+		require.Same(t, &latch, observer.events[0].Latch)
+		require.Equal(t, int64(1), observer.events[1].Count)
+		require.Equal(t, int64(2), observer.events[2].Count)
+	})
+}
+
+func Test_WideBitLatch(t *testing.T) {
+
+	t.Run("NewWideBitLatch() panics on invalid sizes", func(t *testing.T) {
+
+		for _, n := range []int{-1, 0} {
+
+			require.Panics(t, func() {
+
+				_ = NewWideBitLatch(n)
+			})
+		}
+	})
+
+	t.Run("SetBit() sets each bit once and flips on the last", func(t *testing.T) {
+
+		latch := NewWideBitLatch(130)
+
+		require.Equal(t, 130, latch.Len())
+		require.Len(t, latch.Missing(), 130)
+
+		for i := range 130 {
+
+			if i == 64 || i == 129 {
+
+				continue
+			}
+
+			flipped, newlySet := latch.SetBit(i)
+
+			require.False(t, flipped)
+			require.True(t, newlySet)
+		}
+
+		require.Equal(t, []int{64, 129}, latch.Missing())
+		require.Equal(t, 128, latch.NumSet())
+
+		flipped, newlySet := latch.SetBit(0)
+
+		require.False(t, flipped)
+		require.False(t, newlySet)
+
+		flipped, _ = latch.SetBit(129)
+
+		require.False(t, flipped)
+		require.True(t, latch.IsSet(129))
+		require.False(t, latch.IsLatched())
+
+		flipped, _ = latch.SetBit(64)
+
+		require.True(t, flipped)
+		require.True(t, latch.IsLatched())
+		require.Empty(t, latch.Missing())
+	})
+
+	t.Run("SetBit() and IsSet() out of range panic", func(t *testing.T) {
+
+		latch := NewWideBitLatch(100)
+
+		for _, i := range []int{-1, 100, 128} {
+
+			require.Panics(t, func() { latch.SetBit(i) })
+			require.Panics(t, func() { _ = latch.IsSet(i) })
+		}
+	})
+
+	t.Run("concurrent participants flip the latch exactly once", func(t *testing.T) {
+
+		synctesting.VerifyNoLeaks(t)
+
+		const numParticipants = 5_000
+		const numGoroutines = 8
+
+		latch := NewWideBitLatch(numParticipants)
+
+		var numFlips int64
+
+		var wg sync.WaitGroup
+
+		for g := range numGoroutines {
+
+			wg.Go(func() {
+
+				// the goroutines overlap, so that each bit is set by several
+				for i := g * numParticipants / (2 * numGoroutines); i != numParticipants; i++ {
+
+					if flipped, _ := latch.SetBit(i); flipped {
+
+						atomic.AddInt64(&numFlips, 1)
+					}
+				}
+
+				for i := range g * numParticipants / (2 * numGoroutines) {
+
+					if flipped, _ := latch.SetBit(i); flipped {
+
+						atomic.AddInt64(&numFlips, 1)
+					}
+				}
+			})
+		}
+
+		latch.Wait()
+
+		wg.Wait()
+
+		require.Equal(t, int64(1), numFlips)
+		require.Equal(t, numParticipants, latch.NumSet())
+	})
+
+	t.Run("global observer", func(t *testing.T) {
+
+		var global recordingObserver
+
+		previous := SetObserver(&global)
+
+		defer SetObserver(previous)
+
+		latch := NewWideBitLatch(100)
+
+		for i := range 100 {
+
+			latch.SetBit(i)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		cancel()
+
+		require.Nil(t, latch.WaitContext(ctx))
+
+		kinds := global.kinds()
+
+		require.Len(t, kinds, 102)
+		require.Equal(t, LatchCreated, kinds[0])
+		require.Equal(t, LatchFlipped, kinds[100])
+		require.Equal(t, LatchWaited, kinds[101])
+		require.Equal(t, "WideBitLatch", global.events[0].Type)
+		require.Same(t, latch, global.events[1].Latch)
+		require.Equal(t, int64(1), global.events[1].Count)
+		require.Equal(t, int64(100), global.events[100].Count)
+	})
+}
+
+func Benchmark_WideBitLatch_SetBit(b *testing.B) {
+
+	latch := NewWideBitLatch(1 << 20)
+
+	i := 0
+
+	for b.Loop() {
+
+		latch.SetBit(i & (1<<20 - 1))
+
+		i++
+	}
+}
@@ -0,0 +1,384 @@
+// Copyright 2019-2026 Matthew Wilson and Synesis Information Systems. All
+// rights reserved. Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+ * Created: 19th October 2026
+ * Updated:
+ */
+
+// Definition of a circuit breaker.
+
+package sync
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	errCircuitBreakerFailureThresholdMustBePositive = errors.New("circuit breaker failure threshold must be positive")
+	errCircuitBreakerWindowMustBePositive           = errors.New("circuit breaker window must be positive")
+	errCircuitBreakerCooldownMustNotBeNegative      = errors.New("circuit breaker cooldown must not be negative")
+)
+
+// The state of a CircuitBreaker.
+type CircuitState int64
+
+const (
+	// Requests are permitted, and failures are counted.
+	CircuitClosed CircuitState = iota
+	// Requests are refused until the cooldown has elapsed.
+	CircuitOpen
+	// A limited number of probe requests are permitted, the outcome of which
+	// determines whether the circuit closes or reopens.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+
+	switch s {
+	case CircuitClosed:
+
+		return "closed"
+	case CircuitOpen:
+
+		return "open"
+	case CircuitHalfOpen:
+
+		return "half-open"
+	default:
+
+		return "unknown"
+	}
+}
+
+// Settings of a CircuitBreaker. Fields with zero values take the defaults
+// described.
+type CircuitBreakerSettings struct {
+	// The number of failures, within Window, that trips the circuit open.
+	// Must be positive.
+	FailureThreshold int64
+	// The rolling window over which failures are counted. Must be positive.
+	Window time.Duration
+	// The number of buckets into which Window is divided. Defaults to 10.
+	NumBuckets int
+	// The time for which the circuit remains open before permitting
+	// probes.
+	Cooldown time.Duration
+	// The number of probe requests permitted while half-open. Defaults to
+	// 1.
+	MaxProbes int64
+	// Determines whether an error recorded by Record() is a failure.
+	// Defaults to treating every non-nil error as a failure.
+	IsFailure func(err error) bool
+	// Called, synchronously, by the goroutine that effects each state
+	// change. May be nil.
+	OnStateChange func(from, to CircuitState)
+	// Source of time. Defaults to SystemClock.
+	Clock Clock
+}
+
+// A circuit breaker that may be operated safely by multiple concurrent
+// goroutines.
+//
+// While closed, failures are counted in a WindowCounter, and the circuit
+// opens when FailureThreshold failures occur within Window. While open,
+// Allow() refuses requests until Cooldown has elapsed, after which the
+// circuit becomes half-open and permits up to MaxProbes requests: the
+// first success recorded then closes it, and the first failure reopens it.
+//
+// No lock is taken by Allow() or Record(): the success path in the closed
+// state is a single atomic load, and state changes (including the taking of
+// probes) are made by compare-and-swap of a single word, with only the
+// winning goroutine notifying OnStateChange.
+//
+// Since Record() does not identify the request it reports on, the outcome
+// of a request that was permitted in one state and completes in another is
+// attributed to the latter.
+type CircuitBreaker struct {
+	word     int64 // state in the low bits; see makeCircuitWord()
+	failures atomic.Pointer[_circuitFailures]
+	trips    UpCounter
+	settings CircuitBreakerSettings
+	origin   time.Time
+}
+
+// Creates a new CircuitBreaker, in the closed state.
+//
+// Preconditions:
+// - settings.FailureThreshold > 0;
+// - settings.Window > 0;
+// - settings.Cooldown >= 0;
+func NewCircuitBreaker(settings CircuitBreakerSettings) *CircuitBreaker {
+
+	if settings.FailureThreshold <= 0 {
+
+		panic(errCircuitBreakerFailureThresholdMustBePositive)
+	}
+
+	if settings.Window <= 0 {
+
+		panic(errCircuitBreakerWindowMustBePositive)
+	}
+
+	if settings.Cooldown < 0 {
+
+		panic(errCircuitBreakerCooldownMustNotBeNegative)
+	}
+
+	if settings.NumBuckets <= 0 {
+
+		settings.NumBuckets = 10
+	}
+
+	if settings.MaxProbes <= 0 {
+
+		settings.MaxProbes = 1
+	}
+
+	settings.Clock = clockOrSystem(settings.Clock)
+
+	cb := &CircuitBreaker{
+		trips:    NewUpCounter(0),
+		settings: settings,
+		origin:   settings.Clock.Now(),
+	}
+
+	cb.failures.Store(&_circuitFailures{
+		counter: cb.newFailureCounter(),
+	})
+
+	return cb
+}
+
+// The failure counter of one closed period of a CircuitBreaker, identified
+// by the epoch held in the state word during that period.
+type _circuitFailures struct {
+	epoch   int64
+	counter *WindowCounter
+}
+
+// Obtains the failure counter of the closed period of the given epoch,
+// installing it if it is the first to be requested for that period.
+//
+// Since the counter is installed only for a later epoch than that of its
+// predecessor, and a state word of that epoch is established only by the
+// transition into the closed state, a counter is never replaced while it
+// is in use.
+//
+// Returns:
+// the counter; or nil if the period has already been succeeded by another
+func (cb *CircuitBreaker) failuresOf(epoch int64) *WindowCounter {
+
+	for {
+
+		f := cb.failures.Load()
+
+		switch {
+		case f.epoch == epoch:
+
+			return f.counter
+		case f.epoch > epoch:
+
+			return nil
+		}
+
+		cb.failures.CompareAndSwap(f, &_circuitFailures{
+			epoch:   epoch,
+			counter: cb.newFailureCounter(),
+		})
+	}
+}
+
+// Obtains the state word of the closed period that follows the current
+// one, whose failures must have tripped the circuit.
+func (cb *CircuitBreaker) nextClosedWord() int64 {
+
+	return makeCircuitWord(CircuitClosed, cb.failures.Load().epoch+1)
+}
+
+func (cb *CircuitBreaker) newFailureCounter() *WindowCounter {
+
+	width := cb.settings.Window / time.Duration(cb.settings.NumBuckets)
+
+	if width <= 0 {
+
+		width = 1
+	}
+
+	return NewWindowCounter(cb.settings.NumBuckets, width, cb.settings.Clock)
+}
+
+func (cb *CircuitBreaker) now() int64 {
+
+	return int64(cb.settings.Clock.Now().Sub(cb.origin))
+}
+
+const circuitStateBits = 2
+
+// The state of a CircuitBreaker is held in a single word, so that it may
+// be changed by a single compare-and-swap: the low bits hold the
+// CircuitState, and the remaining bits hold, when closed, the epoch of the
+// closed period (see failuresOf()); when open, the time at which the
+// circuit opened (in nanoseconds since origin); and, when half-open, the
+// number of probes permitted.
+func makeCircuitWord(state CircuitState, value int64) int64 {
+
+	return value<<circuitStateBits | int64(state)
+}
+
+func splitCircuitWord(word int64) (state CircuitState, value int64) {
+
+	return CircuitState(word & (1<<circuitStateBits - 1)), word >> circuitStateBits
+}
+
+// Replaces the state word, if it is still from.
+//
+// Returns:
+// true if this caller effected the transition; false otherwise
+func (cb *CircuitBreaker) transition(from, to int64) bool {
+
+	fromState, _ := splitCircuitWord(from)
+	toState, _ := splitCircuitWord(to)
+
+	if !atomic.CompareAndSwapInt64(&cb.word, from, to) {
+
+		return false
+	}
+
+	if toState == CircuitOpen {
+
+		cb.trips.Step()
+	}
+
+	if fn := cb.settings.OnStateChange; fn != nil && fromState != toState {
+
+		fn(fromState, toState)
+	}
+
+	return true
+}
+
+// Obtains the current state of the circuit. An open circuit whose cooldown
+// has elapsed is reported as open until the next call to Allow().
+func (cb *CircuitBreaker) State() CircuitState {
+
+	state, _ := splitCircuitWord(atomic.LoadInt64(&cb.word))
+
+	return state
+}
+
+// Determines whether a request may proceed. Each request that is permitted
+// should have its outcome reported by Record().
+func (cb *CircuitBreaker) Allow() bool {
+
+	for {
+
+		word := atomic.LoadInt64(&cb.word)
+
+		switch state, value := splitCircuitWord(word); state {
+		case CircuitClosed:
+
+			return true
+		case CircuitOpen:
+
+			if cb.now()-value < int64(cb.settings.Cooldown) {
+
+				return false
+			}
+
+			// whether or not this caller effects the transition, the state
+			// is re-examined
+			cb.transition(word, makeCircuitWord(CircuitHalfOpen, 0))
+		case CircuitHalfOpen:
+
+			if value >= cb.settings.MaxProbes {
+
+				return false
+			}
+
+			if cb.transition(word, makeCircuitWord(CircuitHalfOpen, value+1)) {
+
+				return true
+			}
+		}
+	}
+}
+
+// Records the outcome of a request that was permitted by Allow().
+func (cb *CircuitBreaker) Record(err error) {
+
+	word := atomic.LoadInt64(&cb.word)
+
+	state, value := splitCircuitWord(word)
+
+	if !cb.isFailure(err) {
+
+		// the word may change as probes are taken, so retry until either
+		// this or another caller changes the state
+		for state == CircuitHalfOpen && !cb.transition(word, cb.nextClosedWord()) {
+
+			word = atomic.LoadInt64(&cb.word)
+			state, _ = splitCircuitWord(word)
+		}
+
+		return
+	}
+
+	switch state {
+	case CircuitClosed:
+
+		// a failure of a closed period that has since ended is not counted
+		if failures := cb.failuresOf(value); failures != nil {
+
+			failures.Step()
+
+			if failures.Sum(cb.settings.Window) >= cb.settings.FailureThreshold {
+
+				cb.transition(word, makeCircuitWord(CircuitOpen, cb.now()))
+			}
+		}
+	case CircuitHalfOpen:
+
+		for state == CircuitHalfOpen && !cb.transition(word, makeCircuitWord(CircuitOpen, cb.now())) {
+
+			word = atomic.LoadInt64(&cb.word)
+			state, _ = splitCircuitWord(word)
+		}
+	}
+}
+
+func (cb *CircuitBreaker) isFailure(err error) bool {
+
+	if cb.settings.IsFailure != nil {
+
+		return cb.settings.IsFailure(err)
+	}
+
+	return err != nil
+}
+
+// Obtains the number of failures recorded in the current window while
+// closed; or, if the circuit is not closed, in the window of the closed
+// period that tripped it.
+func (cb *CircuitBreaker) Failures() int64 {
+
+	word := atomic.LoadInt64(&cb.word)
+	f := cb.failures.Load()
+
+	if state, epoch := splitCircuitWord(word); state == CircuitClosed && f.epoch != epoch {
+
+		// no failure has yet been recorded in this period
+		return 0
+	}
+
+	return f.counter.Sum(cb.settings.Window)
+}
+
+// Obtains the number of times the circuit has opened.
+func (cb *CircuitBreaker) Trips() int64 {
+
+	return cb.trips.Load()
+}
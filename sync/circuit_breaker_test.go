@@ -0,0 +1,315 @@
+package sync_test
+
+import (
+	. "github.com/synesissoftware/syngo/sync"
+
+	"github.com/synesissoftware/syngo/sync/synctesting"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_CircuitBreaker(t *testing.T) {
+
+	errFailed := errors.New("failed")
+
+	t.Run("NewCircuitBreaker() panics on invalid settings", func(t *testing.T) {
+
+		require.Panics(t, func() {
+
+			NewCircuitBreaker(CircuitBreakerSettings{Window: time.Second})
+		})
+
+		require.Panics(t, func() {
+
+			NewCircuitBreaker(CircuitBreakerSettings{FailureThreshold: 1})
+		})
+
+		require.Panics(t, func() {
+
+			NewCircuitBreaker(CircuitBreakerSettings{FailureThreshold: 1, Window: time.Second, Cooldown: -1})
+		})
+	})
+
+	t.Run("CircuitState String()", func(t *testing.T) {
+
+		require.Equal(t, "closed", CircuitClosed.String())
+		require.Equal(t, "open", CircuitOpen.String())
+		require.Equal(t, "half-open", CircuitHalfOpen.String())
+		require.Equal(t, "unknown", CircuitState(99).String())
+	})
+
+	t.Run("closed -> open -> half-open -> closed", func(t *testing.T) {
+
+		clock := &synctesting.FakeClock{}
+
+		var transitions []string
+
+		cb := NewCircuitBreaker(CircuitBreakerSettings{
+			FailureThreshold: 3,
+			Window:           10 * time.Second,
+			Cooldown:         5 * time.Second,
+			Clock:            clock,
+			OnStateChange: func(from, to CircuitState) {
+
+				transitions = append(transitions, from.String()+"->"+to.String())
+			},
+		})
+
+		require.Equal(t, CircuitClosed, cb.State())
+
+		for i := 0; i != 2; i++ {
+
+			require.True(t, cb.Allow())
+
+			cb.Record(errFailed)
+		}
+
+		require.Equal(t, CircuitClosed, cb.State())
+		require.Equal(t, int64(2), cb.Failures())
+
+		cb.Record(nil)
+
+		require.Equal(t, CircuitClosed, cb.State())
+
+		require.True(t, cb.Allow())
+
+		cb.Record(errFailed)
+
+		require.Equal(t, CircuitOpen, cb.State())
+		require.Equal(t, int64(1), cb.Trips())
+		require.False(t, cb.Allow())
+
+		clock.Advance(4 * time.Second)
+
+		require.False(t, cb.Allow())
+
+		clock.Advance(time.Second)
+
+		require.True(t, cb.Allow())
+		require.Equal(t, CircuitHalfOpen, cb.State())
+
+		// only one probe by default
+		require.False(t, cb.Allow())
+
+		cb.Record(nil)
+
+		require.Equal(t, CircuitClosed, cb.State())
+		require.Equal(t, int64(0), cb.Failures())
+		require.True(t, cb.Allow())
+
+		require.Equal(t, []string{"closed->open", "open->half-open", "half-open->closed"}, transitions)
+	})
+
+	t.Run("a failed probe reopens the circuit", func(t *testing.T) {
+
+		clock := &synctesting.FakeClock{}
+
+		cb := NewCircuitBreaker(CircuitBreakerSettings{
+			FailureThreshold: 1,
+			Window:           time.Second,
+			Cooldown:         time.Second,
+			MaxProbes:        2,
+			Clock:            clock,
+		})
+
+		cb.Record(errFailed)
+
+		require.Equal(t, CircuitOpen, cb.State())
+
+		clock.Advance(time.Second)
+
+		require.True(t, cb.Allow())
+		require.True(t, cb.Allow())
+		require.False(t, cb.Allow())
+
+		cb.Record(errFailed)
+
+		require.Equal(t, CircuitOpen, cb.State())
+		require.Equal(t, int64(2), cb.Trips())
+		require.False(t, cb.Allow())
+
+		clock.Advance(time.Second)
+
+		require.True(t, cb.Allow())
+	})
+
+	t.Run("failures outside the window do not trip the circuit", func(t *testing.T) {
+
+		clock := &synctesting.FakeClock{}
+
+		cb := NewCircuitBreaker(CircuitBreakerSettings{
+			FailureThreshold: 3,
+			Window:           time.Second,
+			Clock:            clock,
+		})
+
+		for i := 0; i != 10; i++ {
+
+			cb.Record(errFailed)
+			cb.Record(errFailed)
+
+			clock.Advance(2 * time.Second)
+		}
+
+		require.Equal(t, CircuitClosed, cb.State())
+	})
+
+	t.Run("IsFailure() classifies errors", func(t *testing.T) {
+
+		errIgnored := errors.New("ignored")
+
+		cb := NewCircuitBreaker(CircuitBreakerSettings{
+			FailureThreshold: 1,
+			Window:           time.Second,
+			IsFailure: func(err error) bool {
+
+				return err != nil && !errors.Is(err, errIgnored)
+			},
+		})
+
+		cb.Record(errIgnored)
+
+		require.Equal(t, CircuitClosed, cb.State())
+
+		cb.Record(errFailed)
+
+		require.Equal(t, CircuitOpen, cb.State())
+	})
+
+	t.Run("failures recorded once closed are not lost to concurrent successful probes", func(t *testing.T) {
+
+		const numProbes = 8
+		const numFailures = 10
+
+		for range 100 {
+
+			clock := &synctesting.FakeClock{}
+
+			cb := NewCircuitBreaker(CircuitBreakerSettings{
+				FailureThreshold: numProbes*numFailures + 1,
+				Window:           time.Minute,
+				Cooldown:         time.Second,
+				MaxProbes:        numProbes,
+				Clock:            clock,
+			})
+
+			for range numProbes*numFailures + 1 {
+
+				cb.Record(errFailed)
+			}
+
+			require.Equal(t, CircuitOpen, cb.State())
+
+			clock.Advance(time.Second)
+
+			for range numProbes {
+
+				require.True(t, cb.Allow())
+			}
+
+			var wg sync.WaitGroup
+
+			for range numProbes {
+
+				wg.Go(func() {
+
+					// the circuit is closed once Record(nil) returns
+					cb.Record(nil)
+
+					for range numFailures {
+
+						cb.Record(errFailed)
+					}
+				})
+			}
+
+			wg.Wait()
+
+			require.Equal(t, CircuitClosed, cb.State())
+			require.Equal(t, int64(numProbes*numFailures), cb.Failures())
+		}
+	})
+
+	t.Run("heavy concurrency trips exactly once and notifies each change once", func(t *testing.T) {
+
+		clock := &synctesting.FakeClock{}
+
+		var numOpened atomic.Int64
+
+		cb := NewCircuitBreaker(CircuitBreakerSettings{
+			FailureThreshold: 100,
+			Window:           time.Minute,
+			Cooldown:         time.Minute,
+			MaxProbes:        5,
+			Clock:            clock,
+			OnStateChange: func(from, to CircuitState) {
+
+				if to == CircuitOpen {
+
+					numOpened.Add(1)
+				}
+			},
+		})
+
+		var wg sync.WaitGroup
+		var allowed atomic.Int64
+
+		for i := 0; i != 10; i++ {
+
+			wg.Go(func() {
+
+				for j := 0; j != 1_000; j++ {
+
+					if cb.Allow() {
+
+						allowed.Add(1)
+
+						if j%2 == 0 {
+
+							cb.Record(errFailed)
+						} else {
+
+							cb.Record(nil)
+						}
+					}
+				}
+			})
+		}
+
+		wg.Wait()
+
+		require.Equal(t, CircuitOpen, cb.State())
+		require.Equal(t, int64(1), numOpened.Load())
+		require.Equal(t, int64(1), cb.Trips())
+
+		clock.Advance(time.Minute)
+
+		var probes atomic.Int64
+
+		for i := 0; i != 10; i++ {
+
+			wg.Go(func() {
+
+				for j := 0; j != 100; j++ {
+
+					if cb.Allow() {
+
+						probes.Add(1)
+					}
+				}
+			})
+		}
+
+		wg.Wait()
+
+		assert.Equal(t, int64(5), probes.Load())
+		assert.Equal(t, CircuitHalfOpen, cb.State())
+	})
+}
@@ -0,0 +1,51 @@
+// Copyright 2019-2026 Matthew Wilson and Synesis Information Systems. All
+// rights reserved. Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+ * Created: 19th October 2026
+ * Updated: 19th October 2026
+ */
+
+// Definition of the clock abstraction used by the timed types.
+
+package sync
+
+import (
+	"time"
+)
+
+// Source of the current time for types whose behaviour depends on it,
+// allowing a deterministic clock to be injected in tests.
+type Clock interface {
+	// Obtains the current time.
+	Now() time.Time
+	// Obtains a channel that receives the current time once d has elapsed.
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+
+	return time.After(d)
+}
+
+// The Clock that reports the system time, used by the timed types when no
+// clock is specified.
+var SystemClock Clock = systemClock{}
+
+func clockOrSystem(clock Clock) Clock {
+
+	if clock == nil {
+
+		return SystemClock
+	}
+
+	return clock
+}
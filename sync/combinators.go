@@ -0,0 +1,333 @@
+// Copyright 2019-2026 Matthew Wilson and Synesis Information Systems. All
+// rights reserved. Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+ * Created: 19th October 2026
+ * Updated:
+ */
+
+// Definition of composite latches that latch when any, or all, of a set of
+// child latches have latched.
+
+package sync
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// Bookkeeping common to the composite latches: the subscriptions held on
+// the children, which are released as soon as the composite latches, or
+// when it is stopped.
+type _composite struct {
+	mx       sync.Mutex
+	unsubs   []func()
+	released bool
+}
+
+func (c *_composite) attach(unsubs []func()) {
+
+	c.mx.Lock()
+
+	if c.released {
+
+		c.mx.Unlock()
+
+		for _, unsub := range unsubs {
+
+			unsub()
+		}
+
+		return
+	}
+
+	c.unsubs = unsubs
+
+	c.mx.Unlock()
+}
+
+// Releases the subscriptions, unless they have already been released.
+//
+// Returns:
+// true if this call released them; false otherwise
+func (c *_composite) release() (released bool) {
+
+	c.mx.Lock()
+
+	unsubs := c.unsubs
+
+	released = !c.released
+
+	c.unsubs = nil
+	c.released = true
+
+	c.mx.Unlock()
+
+	for _, unsub := range unsubs {
+
+		unsub()
+	}
+
+	return
+}
+
+// A latch that latches when the first of its children latches.
+//
+// Children that are latch types from this package are observed without any
+// additional goroutines; for other Latch implementations a single goroutine
+// per child is used, which exits once the composite latches. A composite
+// that may be abandoned before it latches should be stopped, by Stop(), so
+// that those goroutines, and the subscriptions on the other children, are
+// released.
+type AnyLatch struct {
+	_latchNotifier
+	_composite
+	which int64
+}
+
+// Creates a new AnyLatch over the given children. If any child has already
+// latched, the composite is latched on return. An AnyLatch with no children
+// never latches.
+func AnyOf(latches ...Latch) *AnyLatch {
+
+	a := &AnyLatch{
+		which: -1,
+	}
+
+	unsubs := make([]func(), 0, len(latches))
+
+	for i, l := range latches {
+
+		if a._latchNotifier.isFired() {
+
+			break
+		}
+
+		unsubs = append(unsubs, subscribeToLatch(l, func() {
+
+			a.trigger(i)
+		}))
+	}
+
+	a._composite.attach(unsubs)
+
+	return a
+}
+
+func (a *AnyLatch) trigger(index int) {
+
+	// only the first child to latch releases the subscriptions, and then
+	// only if the composite has not been stopped
+	if a._composite.release() {
+
+		atomic.StoreInt64(&a.which, int64(index))
+
+		a._latchNotifier.fire()
+	}
+}
+
+// Obtains the index of the child whose latching caused the composite to
+// latch, or -1 if it has not yet latched.
+func (a *AnyLatch) Which() int {
+
+	return int(atomic.LoadInt64(&a.which))
+}
+
+// Indicates whether any child has latched.
+func (a *AnyLatch) IsLatched() bool {
+
+	return a._latchNotifier.isFired()
+}
+
+// Obtains a channel that is closed when the composite latches.
+func (a *AnyLatch) Done() <-chan struct{} {
+
+	return a._latchNotifier.done()
+}
+
+// Blocks the caller until the composite latches.
+//
+// Returns:
+// the index of the child that caused the composite to latch
+func (a *AnyLatch) Wait() (which int) {
+
+	a._latchNotifier.wait()
+
+	which = a.Which()
+
+	return
+}
+
+// Blocks the caller until the composite latches or ctx is done.
+//
+// Returns:
+// the index of the child that caused the composite to latch, and nil; or
+// -1 and ctx.Err()
+func (a *AnyLatch) WaitContext(ctx context.Context) (which int, err error) {
+
+	if err = a._latchNotifier.waitContext(ctx); err != nil {
+
+		which = -1
+	} else {
+
+		which = a.Which()
+	}
+
+	return
+}
+
+// Stops the composite observing its children, releasing the goroutines
+// and subscriptions held on them. A composite that is stopped before it
+// latches never latches; stopping one that has latched has no effect.
+//
+// Returns:
+// true if this call stopped the composite; false if it had already
+// latched or been stopped
+func (a *AnyLatch) Stop() (stopped bool) {
+
+	return a._composite.release()
+}
+
+// Obtains the number of goroutines currently blocked in Wait() or
+// WaitContext().
+func (a *AnyLatch) NumWaiters() int {
+
+	return int(a._latchNotifier.numWaiters())
+}
+
+// A latch that latches when all of its children have latched.
+//
+// Children that are latch types from this package are observed without any
+// additional goroutines; for other Latch implementations a single goroutine
+// per child is used, which exits once that child latches. A composite that
+// may be abandoned before it latches should be stopped, by Stop(), so that
+// those goroutines, and the subscriptions on the other children, are
+// released.
+type AllLatch struct {
+	_latchNotifier
+	_composite
+	remaining DownLatch
+	which     int64
+}
+
+// Creates a new AllLatch over the given children. An AllLatch with no
+// children is latched on return.
+func AllOf(latches ...Latch) *AllLatch {
+
+	a := &AllLatch{
+		which: -1,
+	}
+
+	if len(latches) == 0 {
+
+		a._composite.release()
+		a._latchNotifier.fire()
+
+		return a
+	}
+
+	a.remaining = NewDownLatch(int64(len(latches)), 0)
+
+	unsubs := make([]func(), 0, len(latches))
+
+	for i, l := range latches {
+
+		unsubs = append(unsubs, subscribeToLatch(l, func() {
+
+			a.trigger(i)
+		}))
+	}
+
+	a._composite.attach(unsubs)
+
+	return a
+}
+
+func (a *AllLatch) trigger(index int) {
+
+	if flipped, _, _ := a.remaining.Step(); flipped && a._composite.release() {
+
+		atomic.StoreInt64(&a.which, int64(index))
+
+		a._latchNotifier.fire()
+	}
+}
+
+// Obtains the index of the child whose latching completed the set, or -1
+// if the composite has not yet latched (or has no children).
+func (a *AllLatch) Which() int {
+
+	return int(atomic.LoadInt64(&a.which))
+}
+
+// Obtains the number of children that have not yet latched.
+func (a *AllLatch) Remaining() int {
+
+	_, count := a.remaining.Load()
+
+	return int(count)
+}
+
+// Indicates whether all children have latched.
+func (a *AllLatch) IsLatched() bool {
+
+	return a._latchNotifier.isFired()
+}
+
+// Obtains a channel that is closed when the composite latches.
+func (a *AllLatch) Done() <-chan struct{} {
+
+	return a._latchNotifier.done()
+}
+
+// Blocks the caller until the composite latches.
+//
+// Returns:
+// the index of the child whose latching completed the set
+func (a *AllLatch) Wait() (which int) {
+
+	a._latchNotifier.wait()
+
+	which = a.Which()
+
+	return
+}
+
+// Blocks the caller until the composite latches or ctx is done.
+//
+// Returns:
+// the index of the child whose latching completed the set, and nil; or -1
+// and ctx.Err()
+func (a *AllLatch) WaitContext(ctx context.Context) (which int, err error) {
+
+	if err = a._latchNotifier.waitContext(ctx); err != nil {
+
+		which = -1
+	} else {
+
+		which = a.Which()
+	}
+
+	return
+}
+
+// Stops the composite observing its children, releasing the goroutines
+// and subscriptions held on them. A composite that is stopped before it
+// latches never latches; stopping one that has latched has no effect.
+//
+// Returns:
+// true if this call stopped the composite; false if it had already
+// latched or been stopped
+func (a *AllLatch) Stop() (stopped bool) {
+
+	return a._composite.release()
+}
+
+// Obtains the number of goroutines currently blocked in Wait() or
+// WaitContext().
+func (a *AllLatch) NumWaiters() int {
+
+	return int(a._latchNotifier.numWaiters())
+}
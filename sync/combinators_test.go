@@ -0,0 +1,402 @@
+package sync_test
+
+import (
+	. "github.com/synesissoftware/syngo/sync"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"context"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// A Latch implementation from outside the package, to exercise the
+// goroutine-based observation path.
+type foreignLatch struct {
+	ch   chan struct{}
+	once sync.Once
+}
+
+func newForeignLatch() *foreignLatch {
+
+	return &foreignLatch{
+		ch: make(chan struct{}),
+	}
+}
+
+func (f *foreignLatch) set() {
+
+	f.once.Do(func() {
+
+		close(f.ch)
+	})
+}
+
+func (f *foreignLatch) IsLatched() bool {
+
+	select {
+	case <-f.ch:
+
+		return true
+	default:
+
+		return false
+	}
+}
+
+func (f *foreignLatch) Done() <-chan struct{} {
+
+	return f.ch
+}
+
+func Test_AnyOf(t *testing.T) {
+
+	t.Run("AnyOf() with no children never latches", func(t *testing.T) {
+
+		composite := AnyOf()
+
+		require.False(t, composite.IsLatched())
+		require.Equal(t, -1, composite.Which())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		which, err := composite.WaitContext(ctx)
+
+		require.Equal(t, -1, which)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("AnyOf() latches when the first child latches", func(t *testing.T) {
+
+		shutdown := NewBoolLatch()
+		work := NewDownLatch(2, 0)
+
+		composite := AnyOf(&shutdown, &work)
+
+		require.False(t, composite.IsLatched())
+		require.Equal(t, -1, composite.Which())
+
+		work.Step()
+
+		require.False(t, composite.IsLatched())
+
+		work.Step()
+
+		require.True(t, composite.IsLatched())
+		require.Equal(t, 1, composite.Which())
+
+		shutdown.Set()
+
+		require.Equal(t, 1, composite.Which())
+		require.Equal(t, 1, composite.Wait())
+
+		select {
+		case <-composite.Done():
+		default:
+
+			require.Fail(t, "Done() channel not closed")
+		}
+	})
+
+	t.Run("AnyOf() over an already-latched child is latched on return", func(t *testing.T) {
+
+		a := NewBoolLatch()
+		b := NewBoolLatch()
+
+		b.Set()
+
+		composite := AnyOf(&a, &b)
+
+		require.True(t, composite.IsLatched())
+		require.Equal(t, 1, composite.Which())
+	})
+
+	t.Run("AnyOf() wakes a blocked waiter", func(t *testing.T) {
+
+		a := NewBoolLatch()
+		b := NewUpLatch(0, 1)
+
+		composite := AnyOf(&a, &b)
+
+		var wg sync.WaitGroup
+		var which int
+
+		wg.Go(func() {
+
+			which = composite.Wait()
+		})
+
+		b.Step()
+
+		wg.Wait()
+
+		require.Equal(t, 1, which)
+	})
+
+	t.Run("AnyOf() over foreign latches", func(t *testing.T) {
+
+		a := newForeignLatch()
+		b := newForeignLatch()
+
+		composite := AnyOf(a, b)
+
+		a.set()
+
+		require.Equal(t, 0, composite.Wait())
+	})
+
+	t.Run("AnyOf() releases goroutines for foreign latches once latched", func(t *testing.T) {
+
+		before := runtime.NumGoroutine()
+
+		latch := NewBoolLatch()
+
+		foreigns := make([]Latch, 0, 101)
+
+		foreigns = append(foreigns, &latch)
+
+		for i := 0; i != 100; i++ {
+
+			foreigns = append(foreigns, newForeignLatch())
+		}
+
+		composite := AnyOf(foreigns...)
+
+		latch.Set()
+
+		require.Equal(t, 0, composite.Wait())
+
+		deadline := time.Now().Add(time.Second)
+
+		for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+
+			time.Sleep(time.Millisecond)
+		}
+
+		require.LessOrEqual(t, runtime.NumGoroutine(), before)
+	})
+
+	t.Run("AnyOf() releases goroutines for foreign latches once stopped", func(t *testing.T) {
+
+		before := runtime.NumGoroutine()
+
+		foreigns := make([]Latch, 0, 100)
+
+		for i := 0; i != 100; i++ {
+
+			foreigns = append(foreigns, newForeignLatch())
+		}
+
+		composite := AnyOf(foreigns...)
+
+		require.True(t, composite.Stop())
+		require.False(t, composite.Stop())
+
+		deadline := time.Now().Add(time.Second)
+
+		for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+
+			time.Sleep(time.Millisecond)
+		}
+
+		require.LessOrEqual(t, runtime.NumGoroutine(), before)
+	})
+
+	t.Run("AnyOf() never latches once stopped", func(t *testing.T) {
+
+		a := NewBoolLatch()
+		f := newForeignLatch()
+
+		composite := AnyOf(&a, f)
+
+		require.True(t, composite.Stop())
+
+		a.Set()
+		f.set()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		which, err := composite.WaitContext(ctx)
+
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Equal(t, -1, which)
+		require.False(t, composite.IsLatched())
+	})
+
+	t.Run("AnyOf() Stop() has no effect once latched", func(t *testing.T) {
+
+		a := NewBoolLatch()
+
+		composite := AnyOf(&a, newForeignLatch())
+
+		a.Set()
+
+		require.False(t, composite.Stop())
+		require.True(t, composite.IsLatched())
+		require.Equal(t, 0, composite.Which())
+	})
+
+	t.Run("AnyOf() composites may be nested", func(t *testing.T) {
+
+		a := NewBoolLatch()
+		b := NewBoolLatch()
+		c := NewBoolLatch()
+
+		inner := AnyOf(&b, &c)
+		outer := AnyOf(&a, inner)
+
+		c.Set()
+
+		require.Equal(t, 1, outer.Wait())
+		require.Equal(t, 1, inner.Which())
+	})
+}
+
+func Test_AllOf(t *testing.T) {
+
+	t.Run("AllOf() with no children is latched", func(t *testing.T) {
+
+		all := AllOf()
+
+		require.True(t, all.IsLatched())
+		require.Equal(t, -1, all.Which())
+		require.Equal(t, 0, all.Remaining())
+	})
+
+	t.Run("AllOf() latches when the last child latches", func(t *testing.T) {
+
+		a := NewBoolLatch()
+		b := NewBoolLatch()
+		c := NewDownLatch(1, 0)
+
+		all := AllOf(&a, &b, &c)
+
+		require.False(t, all.IsLatched())
+		require.Equal(t, 3, all.Remaining())
+
+		b.Set()
+
+		require.False(t, all.IsLatched())
+		require.Equal(t, 2, all.Remaining())
+
+		c.Step()
+
+		require.False(t, all.IsLatched())
+		require.Equal(t, 1, all.Remaining())
+
+		a.Set()
+
+		require.True(t, all.IsLatched())
+		require.Equal(t, 0, all.Remaining())
+		require.Equal(t, 0, all.Which())
+		require.Equal(t, 0, all.Wait())
+	})
+
+	t.Run("AllOf() counts each child once only", func(t *testing.T) {
+
+		a := NewBoolLatch()
+		b := NewBoolLatch()
+
+		all := AllOf(&a, &b)
+
+		a.Set()
+		a.Set()
+		a.Set()
+
+		require.False(t, all.IsLatched())
+
+		b.Set()
+
+		require.True(t, all.IsLatched())
+		require.Equal(t, 1, all.Which())
+	})
+
+	t.Run("AllOf() over children latched from many goroutines", func(t *testing.T) {
+
+		const numLatches = 50
+
+		latches := make([]BoolLatch, numLatches)
+		children := make([]Latch, numLatches)
+
+		for i := range latches {
+
+			children[i] = &latches[i]
+		}
+
+		all := AllOf(children...)
+
+		var wg sync.WaitGroup
+
+		for i := range latches {
+
+			wg.Go(func() {
+
+				latches[i].Set()
+			})
+		}
+
+		wg.Wait()
+
+		which, err := all.WaitContext(context.Background())
+
+		require.Nil(t, err)
+		assert.GreaterOrEqual(t, which, 0)
+		assert.Less(t, which, numLatches)
+	})
+
+	t.Run("AllOf() over mixed latches", func(t *testing.T) {
+
+		a := NewBoolLatch()
+		f := newForeignLatch()
+
+		all := AllOf(&a, f, AnyOf(newForeignLatch(), &a))
+
+		a.Set()
+
+		require.False(t, all.IsLatched())
+
+		f.set()
+
+		require.Equal(t, 1, all.Wait())
+	})
+
+	t.Run("AllOf() never latches once stopped", func(t *testing.T) {
+
+		a := NewBoolLatch()
+		f := newForeignLatch()
+
+		all := AllOf(&a, f)
+
+		a.Set()
+
+		require.Equal(t, 1, all.Remaining())
+		require.True(t, all.Stop())
+		require.False(t, all.Stop())
+
+		f.set()
+
+		time.Sleep(10 * time.Millisecond)
+
+		require.False(t, all.IsLatched())
+		require.Equal(t, -1, all.Which())
+	})
+
+	t.Run("AllOf() Stop() has no effect once latched", func(t *testing.T) {
+
+		require.False(t, AllOf().Stop())
+
+		a := NewBoolLatch()
+
+		all := AllOf(&a)
+
+		a.Set()
+
+		require.False(t, all.Stop())
+		require.True(t, all.IsLatched())
+	})
+}
@@ -0,0 +1,91 @@
+// Copyright 2019-2026 Matthew Wilson and Synesis Information Systems. All
+// rights reserved. Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+ * Created: 19th October 2026
+ * Updated:
+ */
+
+// Adapters between latches and context.Context.
+
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	// The cause (as reported by context.Cause) of a context derived by
+	// LatchContext having been cancelled by its latch.
+	ErrLatched = errors.New("latch latched")
+)
+
+// Creates a context derived from parent that is cancelled when latch
+// latches, with a cause that satisfies errors.Is(cause, ErrLatched). If
+// latch is an *ErrorLatch, the cause also wraps the latch's error, so that
+// it may be obtained from the context by context.Cause.
+//
+// As with context.WithCancel, the caller should call the returned cancel
+// function when the context is no longer needed; doing so (or parent being
+// done) releases the subscription held on latch.
+func LatchContext(parent context.Context, latch Latch) (ctx context.Context, cancel context.CancelFunc) {
+
+	ctx, cancelCause := context.WithCancelCause(parent)
+
+	unsubscribe := subscribeToLatch(latch, func() {
+
+		if el, ok := latch.(*ErrorLatch); ok {
+
+			cancelCause(fmt.Errorf("%w: %w", ErrLatched, el.Err()))
+		} else {
+
+			cancelCause(fmt.Errorf("%w: %T", ErrLatched, latch))
+		}
+	})
+
+	stop := context.AfterFunc(ctx, unsubscribe)
+
+	cancel = func() {
+
+		stop()
+		unsubscribe()
+		cancelCause(context.Canceled)
+	}
+
+	return
+}
+
+// Creates a BoolLatch that is set when ctx is done. If ctx is already done,
+// the latch is set on return.
+//
+// The returned stop function releases the association with ctx, without
+// setting the latch; it returns false if the latch has already been (or is
+// being) set by ctx, as context.AfterFunc.
+func LatchFromContext(ctx context.Context) (latch *BoolLatch, stop func() bool) {
+
+	l := NewBoolLatch()
+
+	latch = &l
+
+	if ctx.Err() != nil {
+
+		latch.Set()
+
+		stop = func() bool {
+
+			return false
+		}
+
+		return
+	}
+
+	stop = context.AfterFunc(ctx, func() {
+
+		latch.Set()
+	})
+
+	return
+}
@@ -0,0 +1,175 @@
+package sync_test
+
+import (
+	. "github.com/synesissoftware/syngo/sync"
+
+	"github.com/stretchr/testify/require"
+
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_LatchContext(t *testing.T) {
+
+	t.Run("context is cancelled when the latch latches", func(t *testing.T) {
+
+		latch := NewBoolLatch()
+
+		ctx, cancel := LatchContext(context.Background(), &latch)
+		defer cancel()
+
+		require.Nil(t, ctx.Err())
+
+		latch.Set()
+
+		<-ctx.Done()
+
+		require.ErrorIs(t, ctx.Err(), context.Canceled)
+		require.ErrorIs(t, context.Cause(ctx), ErrLatched)
+		require.Contains(t, context.Cause(ctx).Error(), "BoolLatch")
+	})
+
+	t.Run("context over an already-latched latch is cancelled", func(t *testing.T) {
+
+		latch := NewDownLatch(1, 0)
+
+		latch.Step()
+
+		ctx, cancel := LatchContext(context.Background(), &latch)
+		defer cancel()
+
+		require.ErrorIs(t, context.Cause(ctx), ErrLatched)
+	})
+
+	t.Run("context over an ErrorLatch carries the latch's error as its cause", func(t *testing.T) {
+
+		latch := NewErrorLatch()
+
+		ctx, cancel := LatchContext(context.Background(), &latch)
+		defer cancel()
+
+		reason := errors.New("disk full")
+
+		latch.Set(reason)
+
+		<-ctx.Done()
+
+		require.ErrorIs(t, context.Cause(ctx), ErrLatched)
+		require.ErrorIs(t, context.Cause(ctx), reason)
+	})
+
+	t.Run("context is cancelled when its parent is", func(t *testing.T) {
+
+		latch := NewBoolLatch()
+
+		parent, parentCancel := context.WithCancelCause(context.Background())
+
+		ctx, cancel := LatchContext(parent, &latch)
+		defer cancel()
+
+		parentCause := errors.New("parent went away")
+
+		parentCancel(parentCause)
+
+		<-ctx.Done()
+
+		require.ErrorIs(t, context.Cause(ctx), parentCause)
+
+		latch.Set()
+
+		require.ErrorIs(t, context.Cause(ctx), parentCause)
+	})
+
+	t.Run("cancel() cancels without the latch latching", func(t *testing.T) {
+
+		latch := NewUpLatch(0, 1)
+
+		ctx, cancel := LatchContext(context.Background(), &latch)
+
+		cancel()
+
+		require.ErrorIs(t, context.Cause(ctx), context.Canceled)
+		require.False(t, latch.IsLatched())
+
+		latch.Step()
+
+		require.False(t, errors.Is(context.Cause(ctx), ErrLatched))
+	})
+}
+
+func Test_LatchFromContext(t *testing.T) {
+
+	t.Run("latch is set when the context is cancelled", func(t *testing.T) {
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		latch, stop := LatchFromContext(ctx)
+		defer stop()
+
+		require.False(t, latch.Load())
+
+		cancel()
+
+		latch.Wait()
+
+		require.True(t, latch.Load())
+	})
+
+	t.Run("latch is set when the context times out", func(t *testing.T) {
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		defer cancel()
+
+		latch, _ := LatchFromContext(ctx)
+
+		latch.Wait()
+
+		require.True(t, latch.IsLatched())
+	})
+
+	t.Run("latch over an already-done context is set on return", func(t *testing.T) {
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		cancel()
+
+		latch, stop := LatchFromContext(ctx)
+
+		require.True(t, latch.Load())
+		require.False(t, stop())
+	})
+
+	t.Run("stop() releases the context without setting the latch", func(t *testing.T) {
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		latch, stop := LatchFromContext(ctx)
+
+		require.True(t, stop())
+
+		cancel()
+
+		time.Sleep(5 * time.Millisecond)
+
+		require.False(t, latch.Load())
+	})
+
+	t.Run("round-trip through both adapters", func(t *testing.T) {
+
+		shutdown := NewBoolLatch()
+
+		ctx, cancel := LatchContext(context.Background(), &shutdown)
+		defer cancel()
+
+		latch, stop := LatchFromContext(ctx)
+		defer stop()
+
+		shutdown.Set()
+
+		latch.Wait()
+
+		require.True(t, latch.Load())
+	})
+}
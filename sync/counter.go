@@ -4,7 +4,7 @@
 
 /*
  * Created: 15th November 2025
- * Updated:
+ * Updated: 19th October 2026
  */
 
 // Definition of a number of types that have one-way behaviour.
@@ -12,18 +12,27 @@
 package sync
 
 import (
+	"context"
+	"iter"
+	"sync"
 	"sync/atomic"
 )
 
 // Count-down-to-zero (and beyond, a bit) numeric latch
 type _baseCounter struct {
-	value int64
+	value     int64
+	initial   int64
+	direction int64
+	monotonic bool
+	waiters   _counterWaiters
 }
 
 func (l *_baseCounter) step(increment int64) (newCount int64) {
 
 	newCount = atomic.AddInt64(&l.value, increment)
 
+	l.waiters.notify(newCount)
+
 	return
 }
 
@@ -34,6 +43,264 @@ func (l *_baseCounter) load() (count int64) {
 	return
 }
 
+// Indicates whether moving from from to to would be against the counter's
+// direction, and is therefore forbidden for a monotonic counter.
+func (l *_baseCounter) isForbidden(from, to int64) bool {
+
+	if !l.monotonic {
+
+		return false
+	}
+
+	if l.direction < 0 {
+
+		return to > from
+	} else {
+
+		return to < from
+	}
+}
+
+func (l *_baseCounter) swap(newCount int64) (oldCount int64, swapped bool) {
+
+	if !l.monotonic {
+
+		oldCount = atomic.SwapInt64(&l.value, newCount)
+		swapped = true
+
+		l.waiters.notify(newCount)
+
+		return
+	}
+
+	for {
+
+		oldCount = atomic.LoadInt64(&l.value)
+
+		if l.isForbidden(oldCount, newCount) {
+
+			return
+		}
+
+		if atomic.CompareAndSwapInt64(&l.value, oldCount, newCount) {
+
+			swapped = true
+
+			l.waiters.notify(newCount)
+
+			return
+		}
+	}
+}
+
+func (l *_baseCounter) compareAndSwap(oldCount, newCount int64) (swapped bool) {
+
+	if l.isForbidden(oldCount, newCount) {
+
+		return
+	}
+
+	if atomic.CompareAndSwapInt64(&l.value, oldCount, newCount) {
+
+		swapped = true
+
+		l.waiters.notify(newCount)
+	}
+
+	return
+}
+
+// Blocks until the value satisfies reached, or ctx (if non-nil) is done.
+func (l *_baseCounter) waitFor(ctx context.Context, reached func(int64) bool) (count int64, err error) {
+
+	return l.waiters.waitFor(ctx, l.load, reached)
+}
+
+// Obtains a sequence of the values of the counter, beginning with the
+// current value, and then each value that differs from the one previously
+// yielded. Values are coalesced: if the value changes more than once
+// before the consumer is ready, only the latest is yielded. The sequence
+// ends when ctx is done.
+func (l *_baseCounter) values(ctx context.Context) iter.Seq[int64] {
+
+	return func(yield func(int64) bool) {
+
+		if ctx.Err() != nil {
+
+			return
+		}
+
+		for last := l.load(); yield(last); {
+
+			for {
+
+				if _, err := l.waitFor(ctx, func(count int64) bool {
+
+					return count != last
+				}); err != nil {
+
+					return
+				}
+
+				// the value may have changed again, or changed back
+				if latest := l.load(); latest != last {
+
+					last = latest
+
+					break
+				}
+			}
+		}
+	}
+}
+
+// Obtains a channel that receives the value once it differs from since.
+func (l *_baseCounter) changed(since int64) <-chan int64 {
+
+	reached := func(count int64) bool {
+
+		return count != since
+	}
+
+	w := l.waiters.add(reached)
+
+	if count := l.load(); reached(count) && l.waiters.remove(w) {
+
+		w.ch <- count
+	}
+
+	return w.ch
+}
+
+type _counterWaiter struct {
+	reached func(int64) bool
+	ch      chan int64
+}
+
+// The set of goroutines waiting on a counter (or a numeric latch) for a
+// condition on its value.
+//
+// Stepping a counter with no waiters costs a single atomic load.
+type _counterWaiters struct {
+	numWaiters int64
+	mx         sync.Mutex
+	waiters    map[*_counterWaiter]struct{}
+	waiting    _waiterCount
+}
+
+func (ws *_counterWaiters) add(reached func(int64) bool) (w *_counterWaiter) {
+
+	w = &_counterWaiter{
+		reached: reached,
+		ch:      make(chan int64, 1),
+	}
+
+	ws.mx.Lock()
+
+	if ws.waiters == nil {
+
+		ws.waiters = make(map[*_counterWaiter]struct{})
+	}
+
+	ws.waiters[w] = struct{}{}
+
+	atomic.AddInt64(&ws.numWaiters, 1)
+
+	ws.mx.Unlock()
+
+	return
+}
+
+// Removes w, if it has not already been notified.
+//
+// Returns:
+// true if w was removed; false if it had been notified
+func (ws *_counterWaiters) remove(w *_counterWaiter) (removed bool) {
+
+	ws.mx.Lock()
+
+	if _, removed = ws.waiters[w]; removed {
+
+		delete(ws.waiters, w)
+
+		atomic.AddInt64(&ws.numWaiters, -1)
+	}
+
+	ws.mx.Unlock()
+
+	return
+}
+
+// Blocks until the value obtained from load satisfies reached, or ctx (if
+// non-nil) is done.
+func (ws *_counterWaiters) waitFor(ctx context.Context, load func() int64, reached func(int64) bool) (count int64, err error) {
+
+	w := ws.add(reached)
+
+	// re-check after registering, in case the value moved before the
+	// registration was visible to the stepping goroutine
+	if count = load(); reached(count) && ws.remove(w) {
+
+		return
+	}
+
+	token := ws.waiting.begin()
+	defer ws.waiting.end(token)
+
+	if ctx == nil {
+
+		count = <-w.ch
+
+		return
+	}
+
+	select {
+	case count = <-w.ch:
+
+		return
+	case <-ctx.Done():
+
+		if ws.remove(w) {
+
+			err = ctx.Err()
+		} else {
+
+			// notified concurrently with cancellation
+			count = <-w.ch
+		}
+
+		return
+	}
+}
+
+// Notifies, and removes, those waiters for which count is reached.
+func (ws *_counterWaiters) notify(count int64) {
+
+	if 0 != atomic.LoadInt64(&ws.numWaiters) {
+
+		ws.notifySlow(count)
+	}
+}
+
+func (ws *_counterWaiters) notifySlow(count int64) {
+
+	ws.mx.Lock()
+
+	for w := range ws.waiters {
+
+		if w.reached(count) {
+
+			delete(ws.waiters, w)
+
+			atomic.AddInt64(&ws.numWaiters, -1)
+
+			w.ch <- count
+		}
+	}
+
+	ws.mx.Unlock()
+}
+
 // A unidirectional latch that counts down from an initial value to a lower
 // threshold that may be operated safely by multiple concurrent goroutines.
 type DownCounter struct {
@@ -49,7 +316,24 @@ func NewDownCounter(initialValue int64) DownCounter {
 
 	return DownCounter{
 		_baseCounter: _baseCounter{
-			value: initialValue,
+			value:     initialValue,
+			initial:   initialValue,
+			direction: -1,
+		},
+	}
+}
+
+// Creates a new monotonic DownCounter, for which Store(), Swap(),
+// CompareAndSwap(), and LoadAndReset() fail (with no effect) if they would
+// move the value up.
+func NewMonotonicDownCounter(initialValue int64) DownCounter {
+
+	return DownCounter{
+		_baseCounter: _baseCounter{
+			value:     initialValue,
+			initial:   initialValue,
+			direction: -1,
+			monotonic: true,
 		},
 	}
 }
@@ -69,6 +353,123 @@ func (l *DownCounter) Load() (count int64) {
 	return
 }
 
+// Blocks the caller until the value of the counter is at or below target.
+//
+// Returns:
+// the value that satisfied the wait
+func (l *DownCounter) WaitDownTo(target int64) (count int64) {
+
+	count, _ = l._baseCounter.waitFor(nil, func(count int64) bool {
+
+		return count <= target
+	})
+
+	return
+}
+
+// Blocks the caller until the value of the counter is at or below target,
+// or ctx is done.
+//
+// Returns:
+// the value that satisfied the wait and nil; or ctx.Err()
+func (l *DownCounter) WaitDownToContext(ctx context.Context, target int64) (count int64, err error) {
+
+	count, err = l._baseCounter.waitFor(ctx, func(count int64) bool {
+
+		return count <= target
+	})
+
+	return
+}
+
+// Obtains a channel that receives the value of the counter once it differs
+// from since. If it already differs, the value is available immediately.
+//
+// The channel receives at most one value, and is never closed; a
+// registration is held until the value changes.
+func (l *DownCounter) Changed(since int64) <-chan int64 {
+
+	return l._baseCounter.changed(since)
+}
+
+// Obtains a sequence of the values of the counter, beginning with the
+// current value, and then each value that differs from the one previously
+// yielded, e.g.:
+//
+//	for count := range counter.Values(ctx) {
+//		...
+//	}
+//
+// Values are coalesced: if the value changes more than once before the
+// consumer is ready, only the latest is yielded. The sequence ends when
+// ctx is done, or when the consumer stops iterating.
+func (l *DownCounter) Values(ctx context.Context) iter.Seq[int64] {
+
+	return l._baseCounter.values(ctx)
+}
+
+// Obtains the number of goroutines currently blocked in WaitDownTo() or
+// WaitDownToContext(), or awaiting the next value in an iteration of
+// Values().
+func (l *DownCounter) NumWaiters() int {
+
+	return int(l._baseCounter.waiters.waiting.load())
+}
+
+// Sets the value of the counter.
+//
+// Returns:
+// true if the value was stored; false if the counter is monotonic and
+// newCount is above the current value
+func (l *DownCounter) Store(newCount int64) (stored bool) {
+
+	_, stored = l._baseCounter.swap(newCount)
+
+	return
+}
+
+// Sets the value of the counter, obtaining the value it replaced.
+//
+// Returns:
+// oldCount - the value prior to the call;
+// swapped - true if the value was replaced; false if the counter is
+// monotonic and newCount is above oldCount
+func (l *DownCounter) Swap(newCount int64) (oldCount int64, swapped bool) {
+
+	oldCount, swapped = l._baseCounter.swap(newCount)
+
+	return
+}
+
+// Sets the value of the counter to newCount if it is currently oldCount.
+// For a monotonic counter, fails if newCount is above oldCount.
+//
+// Returns:
+// true if the value was replaced; false otherwise
+func (l *DownCounter) CompareAndSwap(oldCount, newCount int64) (swapped bool) {
+
+	swapped = l._baseCounter.compareAndSwap(oldCount, newCount)
+
+	return
+}
+
+// Obtains the current value of the counter and resets it to its initial
+// value, as a single atomic operation; useful for periodic reporting.
+//
+// A monotonic counter cannot be reset once it has moved from its initial
+// value, since that would move it up.
+//
+// Returns:
+// count - the value prior to the call;
+// reset - true if the value was reset; false if the counter is monotonic
+// and has moved
+func (l *DownCounter) LoadAndReset() (count int64, reset bool) {
+
+	count, reset = l._baseCounter.swap(l._baseCounter.initial)
+
+	return
+}
+
 // A unidirectional latch that counts up from an initial value to a higher
 // threshold that may be operated safely by multiple concurrent goroutines.
 type UpCounter struct {
@@ -84,7 +485,24 @@ func NewUpCounter(initialValue int64) UpCounter {
 
 	return UpCounter{
 		_baseCounter: _baseCounter{
-			value: initialValue,
+			value:     initialValue,
+			initial:   initialValue,
+			direction: 1,
+		},
+	}
+}
+
+// Creates a new monotonic UpCounter, for which Store(), Swap(),
+// CompareAndSwap(), and LoadAndReset() fail (with no effect) if they would
+// move the value down.
+func NewMonotonicUpCounter(initialValue int64) UpCounter {
+
+	return UpCounter{
+		_baseCounter: _baseCounter{
+			value:     initialValue,
+			initial:   initialValue,
+			direction: 1,
+			monotonic: true,
 		},
 	}
 }
@@ -103,3 +521,122 @@ func (l *UpCounter) Load() (count int64) {
 
 	return
 }
+
+// Blocks the caller until the value of the counter is at or above target.
+// Only those waiters whose targets have been reached are woken by each
+// step.
+//
+// Returns:
+// the value that satisfied the wait
+func (l *UpCounter) WaitUntil(target int64) (count int64) {
+
+	count, _ = l._baseCounter.waitFor(nil, func(count int64) bool {
+
+		return count >= target
+	})
+
+	return
+}
+
+// Blocks the caller until the value of the counter is at or above target,
+// or ctx is done.
+//
+// Returns:
+// the value that satisfied the wait and nil; or ctx.Err()
+func (l *UpCounter) WaitUntilContext(ctx context.Context, target int64) (count int64, err error) {
+
+	count, err = l._baseCounter.waitFor(ctx, func(count int64) bool {
+
+		return count >= target
+	})
+
+	return
+}
+
+// Obtains a channel that receives the value of the counter once it differs
+// from since. If it already differs, the value is available immediately.
+//
+// The channel receives at most one value, and is never closed; a
+// registration is held until the value changes.
+func (l *UpCounter) Changed(since int64) <-chan int64 {
+
+	return l._baseCounter.changed(since)
+}
+
+// Obtains a sequence of the values of the counter, beginning with the
+// current value, and then each value that differs from the one previously
+// yielded, e.g.:
+//
+//	for count := range counter.Values(ctx) {
+//		...
+//	}
+//
+// Values are coalesced: if the value changes more than once before the
+// consumer is ready, only the latest is yielded. The sequence ends when
+// ctx is done, or when the consumer stops iterating.
+func (l *UpCounter) Values(ctx context.Context) iter.Seq[int64] {
+
+	return l._baseCounter.values(ctx)
+}
+
+// Obtains the number of goroutines currently blocked in WaitUntil() or
+// WaitUntilContext(), or awaiting the next value in an iteration of
+// Values().
+func (l *UpCounter) NumWaiters() int {
+
+	return int(l._baseCounter.waiters.waiting.load())
+}
+
+// Sets the value of the counter.
+//
+// Returns:
+// true if the value was stored; false if the counter is monotonic and
+// newCount is below the current value
+func (l *UpCounter) Store(newCount int64) (stored bool) {
+
+	_, stored = l._baseCounter.swap(newCount)
+
+	return
+}
+
+// Sets the value of the counter, obtaining the value it replaced.
+//
+// Returns:
+// oldCount - the value prior to the call;
+// swapped - true if the value was replaced; false if the counter is
+// monotonic and newCount is below oldCount
+func (l *UpCounter) Swap(newCount int64) (oldCount int64, swapped bool) {
+
+	oldCount, swapped = l._baseCounter.swap(newCount)
+
+	return
+}
+
+// Sets the value of the counter to newCount if it is currently oldCount.
+// For a monotonic counter, fails if newCount is below oldCount.
+//
+// Returns:
+// true if the value was replaced; false otherwise
+func (l *UpCounter) CompareAndSwap(oldCount, newCount int64) (swapped bool) {
+
+	swapped = l._baseCounter.compareAndSwap(oldCount, newCount)
+
+	return
+}
+
+// Obtains the current value of the counter and resets it to its initial
+// value, as a single atomic operation; useful for periodic reporting.
+//
+// A monotonic counter cannot be reset once it has moved from its initial
+// value, since that would move it down.
+//
+// Returns:
+// count - the value prior to the call;
+// reset - true if the value was reset; false if the counter is monotonic
+// and has moved
+func (l *UpCounter) LoadAndReset() (count int64, reset bool) {
+
+	count, reset = l._baseCounter.swap(l._baseCounter.initial)
+
+	return
+}
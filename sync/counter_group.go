@@ -0,0 +1,232 @@
+// Copyright 2019-2026 Matthew Wilson and Synesis Information Systems. All
+// rights reserved. Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+ * Created: 19th October 2026
+ * Updated:
+ */
+
+// Definition of a group of counters that may be snapshotted consistently.
+
+package sync
+
+import (
+	"errors"
+	"reflect"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	errCounterGroupTypeMustBeStructOfInt64 = errors.New("counter group type must be a struct whose fields are all exported int64s")
+	errCounterGroupUnknownCounter          = errors.New("counter group has no such counter")
+)
+
+// One of the two banks of a counter group, into which the increments of
+// one epoch are made.
+type _counterGroupBank struct {
+	inflight int64 // the number of increments that may be made to values
+	values   []int64
+}
+
+// The untyped state of a CounterGroup.
+//
+// Increments are made into the bank of the current epoch. A snapshot
+// advances the epoch, so that subsequent increments are made into the
+// other bank, waits for the increments in flight into the old bank to
+// complete, and then folds the old bank into the totals. An increment
+// announces itself in the bank's inflight count and then re-checks the
+// epoch, backing out if it has changed; so every increment falls wholly
+// before or wholly after the advance of the epoch, which is the instant at
+// which the snapshot is taken.
+type _counterGroup struct {
+	epoch  uint64
+	banks  [2]_counterGroupBank
+	mx     sync.Mutex
+	totals []int64 // guarded by mx
+}
+
+func newCounterGroup(n int) (g _counterGroup) {
+
+	g.banks[0].values = make([]int64, n)
+	g.banks[1].values = make([]int64, n)
+	g.totals = make([]int64, n)
+
+	return
+}
+
+func (g *_counterGroup) add(index int, n int64) {
+
+	for {
+
+		epoch := atomic.LoadUint64(&g.epoch)
+		bank := &g.banks[epoch&1]
+
+		atomic.AddInt64(&bank.inflight, 1)
+
+		if atomic.LoadUint64(&g.epoch) == epoch {
+
+			atomic.AddInt64(&bank.values[index], n)
+			atomic.AddInt64(&bank.inflight, -1)
+
+			return
+		}
+
+		// a snapshot intervened, and may be waiting on this bank
+		atomic.AddInt64(&bank.inflight, -1)
+	}
+}
+
+func (g *_counterGroup) snapshot(reset bool) (values []int64) {
+
+	g.mx.Lock()
+	defer g.mx.Unlock()
+
+	epoch := atomic.AddUint64(&g.epoch, 1) - 1
+	bank := &g.banks[epoch&1]
+
+	// only increments that observed the old epoch remain, and each is but a
+	// few instructions
+	for atomic.LoadInt64(&bank.inflight) != 0 {
+
+		runtime.Gosched()
+	}
+
+	values = make([]int64, len(g.totals))
+
+	for i := range g.totals {
+
+		g.totals[i] += atomic.SwapInt64(&bank.values[i], 0)
+
+		values[i] = g.totals[i]
+
+		if reset {
+
+			g.totals[i] = 0
+		}
+	}
+
+	return
+}
+
+// A counter that is a member of a CounterGroup.
+type GroupCounter struct {
+	group *_counterGroup
+	index int
+}
+
+// Increments the counter.
+//
+// Unlike UpCounter.Step(), the new value is not returned, since it is
+// known only to a snapshot.
+func (c *GroupCounter) Step() {
+
+	c.group.add(c.index, 1)
+}
+
+// Adds n to the counter.
+func (c *GroupCounter) Add(n int64) {
+
+	c.group.add(c.index, n)
+}
+
+// A group of counters, defined by the int64 fields of the struct type T,
+// that may be incremented without locks by multiple concurrent goroutines,
+// and whose values may be obtained together, as a T, by a consistent
+// snapshot.
+//
+// Whereas loading a number of separate counters in turn may observe an
+// increment to one but not to another incremented before it - so that,
+// for example, a count of errors exceeds a count of requests - a snapshot
+// of a group observes all the increments made before some instant, and
+// none made after it. Snapshots are serialised with one another, and wait
+// only for increments already in progress; they do not block increments.
+//
+// Increments contend on a per-group word, so a group is more expensive to
+// increment under contention than are separate counters.
+type CounterGroup[T any] struct {
+	_counterGroup
+	fields map[string]int
+}
+
+// Creates a new CounterGroup, whose counters are the fields of T, each
+// initially 0.
+//
+// Preconditions:
+// - T is a struct type whose fields are all exported and of type int64,
+// and of which there is at least one;
+func NewCounterGroup[T any]() *CounterGroup[T] {
+
+	t := reflect.TypeFor[T]()
+
+	if t.Kind() != reflect.Struct || t.NumField() == 0 {
+
+		panic(errCounterGroupTypeMustBeStructOfInt64)
+	}
+
+	fields := make(map[string]int, t.NumField())
+
+	for i := range t.NumField() {
+
+		f := t.Field(i)
+
+		if !f.IsExported() || f.Type.Kind() != reflect.Int64 {
+
+			panic(errCounterGroupTypeMustBeStructOfInt64)
+		}
+
+		fields[f.Name] = i
+	}
+
+	return &CounterGroup[T]{
+		_counterGroup: newCounterGroup(t.NumField()),
+		fields:        fields,
+	}
+}
+
+// Obtains the counter of the given field of T.
+//
+// Preconditions:
+// - T has an int64 field of the given name;
+func (g *CounterGroup[T]) Counter(field string) *GroupCounter {
+
+	index, ok := g.fields[field]
+
+	if !ok {
+
+		panic(errCounterGroupUnknownCounter)
+	}
+
+	return &GroupCounter{
+		group: &g._counterGroup,
+		index: index,
+	}
+}
+
+func (g *CounterGroup[T]) toT(values []int64) (t T) {
+
+	v := reflect.ValueOf(&t).Elem()
+
+	for i, value := range values {
+
+		v.Field(i).SetInt(value)
+	}
+
+	return
+}
+
+// Obtains the values of all counters as at a single instant.
+func (g *CounterGroup[T]) Snapshot() T {
+
+	return g.toT(g._counterGroup.snapshot(false))
+}
+
+// Obtains the values of all counters as at a single instant, and resets
+// them all to 0 at that instant, so that no increment is lost or counted
+// twice between successive calls; useful for periodic reporting.
+func (g *CounterGroup[T]) SnapshotAndReset() T {
+
+	return g.toT(g._counterGroup.snapshot(true))
+}
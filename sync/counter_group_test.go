@@ -0,0 +1,157 @@
+package sync_test
+
+import (
+	. "github.com/synesissoftware/syngo/sync"
+
+	"github.com/stretchr/testify/require"
+
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+type requestStats struct {
+	Requests int64
+	Errors   int64
+}
+
+func Test_CounterGroup(t *testing.T) {
+
+	t.Run("NewCounterGroup() panics on unsuitable types", func(t *testing.T) {
+
+		require.Panics(t, func() { _ = NewCounterGroup[int64]() })
+		require.Panics(t, func() { _ = NewCounterGroup[struct{}]() })
+		require.Panics(t, func() { _ = NewCounterGroup[struct{ A int32 }]() })
+		require.Panics(t, func() { _ = NewCounterGroup[struct{ a int64 }]() })
+	})
+
+	t.Run("Counter() of an unknown field panics", func(t *testing.T) {
+
+		group := NewCounterGroup[requestStats]()
+
+		require.Panics(t, func() { _ = group.Counter("Timeouts") })
+	})
+
+	t.Run("Snapshot() returns the values of the counters", func(t *testing.T) {
+
+		group := NewCounterGroup[requestStats]()
+
+		require.Equal(t, requestStats{}, group.Snapshot())
+
+		requests := group.Counter("Requests")
+		errors := group.Counter("Errors")
+
+		requests.Step()
+		requests.Step()
+		requests.Add(3)
+		errors.Step()
+
+		require.Equal(t, requestStats{Requests: 5, Errors: 1}, group.Snapshot())
+
+		requests.Add(-1)
+
+		require.Equal(t, requestStats{Requests: 4, Errors: 1}, group.Snapshot())
+		require.Equal(t, requestStats{Requests: 4, Errors: 1}, group.Snapshot())
+	})
+
+	t.Run("SnapshotAndReset() resets the counters", func(t *testing.T) {
+
+		group := NewCounterGroup[requestStats]()
+
+		group.Counter("Requests").Add(10)
+		group.Counter("Errors").Add(2)
+
+		require.Equal(t, requestStats{Requests: 10, Errors: 2}, group.SnapshotAndReset())
+		require.Equal(t, requestStats{}, group.Snapshot())
+
+		group.Counter("Errors").Step()
+
+		require.Equal(t, requestStats{Errors: 1}, group.SnapshotAndReset())
+	})
+
+	t.Run("concurrent snapshots are consistent and lose no increments", func(t *testing.T) {
+
+		const numGoroutines = 8
+		const numRequests = 5_000
+
+		group := NewCounterGroup[requestStats]()
+
+		requests := group.Counter("Requests")
+		errors := group.Counter("Errors")
+
+		var stop int64
+		var numInconsistent int64
+		var reported requestStats
+
+		var snapshotter sync.WaitGroup
+
+		snapshotter.Go(func() {
+
+			for atomic.LoadInt64(&stop) == 0 {
+
+				// every request errors, and is counted before its error, so the
+				// errors since the last reset are for requests since then, or
+				// for those outstanding at the reset
+				s := group.Snapshot()
+
+				if s.Errors > s.Requests+reported.Requests-reported.Errors {
+
+					atomic.AddInt64(&numInconsistent, 1)
+				}
+
+				p := group.SnapshotAndReset()
+
+				if p.Errors > p.Requests+reported.Requests-reported.Errors {
+
+					atomic.AddInt64(&numInconsistent, 1)
+				}
+
+				reported.Requests += p.Requests
+				reported.Errors += p.Errors
+			}
+		})
+
+		var wg sync.WaitGroup
+
+		for range numGoroutines {
+
+			wg.Go(func() {
+
+				for range numRequests {
+
+					requests.Step()
+					errors.Step()
+				}
+			})
+		}
+
+		wg.Wait()
+
+		atomic.StoreInt64(&stop, 1)
+
+		snapshotter.Wait()
+
+		final := group.SnapshotAndReset()
+
+		reported.Requests += final.Requests
+		reported.Errors += final.Errors
+
+		require.Zero(t, numInconsistent)
+		require.Equal(t, requestStats{Requests: numGoroutines * numRequests, Errors: numGoroutines * numRequests}, reported)
+	})
+}
+
+func Benchmark_GroupCounter_Step(b *testing.B) {
+
+	group := NewCounterGroup[requestStats]()
+
+	requests := group.Counter("Requests")
+
+	b.RunParallel(func(pb *testing.PB) {
+
+		for pb.Next() {
+
+			requests.Step()
+		}
+	})
+}
@@ -6,8 +6,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"context"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func Test_DownCounter(t *testing.T) {
@@ -222,3 +225,512 @@ func Test_UpCounter(t *testing.T) {
 		require.Equal(t, totalLoadCount, counter.Load())
 	})
 }
+
+func Test_Counter_Swap_and_friends(t *testing.T) {
+
+	t.Run("DownCounter Store() and Swap()", func(t *testing.T) {
+
+		counter := NewDownCounter(10)
+
+		counter.Step()
+
+		require.True(t, counter.Store(20))
+		require.Equal(t, int64(20), counter.Load())
+
+		old, swapped := counter.Swap(5)
+
+		require.True(t, swapped)
+		require.Equal(t, int64(20), old)
+		require.Equal(t, int64(5), counter.Load())
+	})
+
+	t.Run("UpCounter CompareAndSwap()", func(t *testing.T) {
+
+		counter := NewUpCounter(0)
+
+		counter.Step()
+
+		require.False(t, counter.CompareAndSwap(0, 10))
+		require.Equal(t, int64(1), counter.Load())
+
+		require.True(t, counter.CompareAndSwap(1, 10))
+		require.Equal(t, int64(10), counter.Load())
+
+		require.True(t, counter.CompareAndSwap(10, -10))
+		require.Equal(t, int64(-10), counter.Load())
+	})
+
+	t.Run("UpCounter LoadAndReset() resets to the initial value", func(t *testing.T) {
+
+		counter := NewUpCounter(3)
+
+		counter.Step()
+		counter.Step()
+
+		count, reset := counter.LoadAndReset()
+
+		require.True(t, reset)
+		require.Equal(t, int64(5), count)
+		require.Equal(t, int64(3), counter.Load())
+	})
+
+	t.Run("monotonic UpCounter forbids moving down", func(t *testing.T) {
+
+		counter := NewMonotonicUpCounter(0)
+
+		counter.Step()
+
+		require.False(t, counter.Store(0))
+		require.Equal(t, int64(1), counter.Load())
+
+		require.True(t, counter.Store(1))
+		require.True(t, counter.Store(7))
+		require.Equal(t, int64(7), counter.Load())
+
+		old, swapped := counter.Swap(6)
+
+		require.False(t, swapped)
+		require.Equal(t, int64(7), old)
+		require.Equal(t, int64(7), counter.Load())
+
+		require.False(t, counter.CompareAndSwap(7, 6))
+		require.True(t, counter.CompareAndSwap(7, 8))
+
+		count, reset := counter.LoadAndReset()
+
+		require.False(t, reset)
+		require.Equal(t, int64(8), count)
+		require.Equal(t, int64(8), counter.Load())
+	})
+
+	t.Run("monotonic DownCounter forbids moving up", func(t *testing.T) {
+
+		counter := NewMonotonicDownCounter(10)
+
+		count, reset := counter.LoadAndReset()
+
+		require.True(t, reset)
+		require.Equal(t, int64(10), count)
+
+		counter.Step()
+
+		require.False(t, counter.Store(10))
+		require.True(t, counter.Store(0))
+
+		old, swapped := counter.Swap(-3)
+
+		require.True(t, swapped)
+		require.Equal(t, int64(0), old)
+
+		require.False(t, counter.CompareAndSwap(-3, -2))
+		require.Equal(t, int64(-3), counter.Load())
+	})
+
+	t.Run("LoadAndReset() from a reporter loses no steps", func(t *testing.T) {
+
+		counter := NewUpCounter(0)
+
+		const numGoroutines = 10
+		const numSteps = 10_000
+
+		var wg sync.WaitGroup
+		var reported int64
+
+		done := NewBoolLatch()
+
+		for i := 0; i != numGoroutines; i++ {
+
+			wg.Go(func() {
+
+				for j := 0; j != numSteps; j++ {
+
+					_ = counter.Step()
+				}
+			})
+		}
+
+		var reporter sync.WaitGroup
+
+		reporter.Go(func() {
+
+			for !done.Load() {
+
+				count, _ := counter.LoadAndReset()
+
+				reported += count
+			}
+		})
+
+		wg.Wait()
+
+		done.Set()
+
+		reporter.Wait()
+
+		count, _ := counter.LoadAndReset()
+
+		reported += count
+
+		require.Equal(t, int64(numGoroutines*numSteps), reported)
+	})
+
+	t.Run("monotonic counter Store() from many goroutines only ever advances", func(t *testing.T) {
+
+		counter := NewMonotonicUpCounter(0)
+
+		var wg sync.WaitGroup
+
+		for i := 0; i != 10; i++ {
+
+			wg.Go(func() {
+
+				for j := int64(0); j != 1_000; j++ {
+
+					before := counter.Load()
+
+					if counter.Store(j) {
+
+						assert.GreaterOrEqual(t, j, before)
+					}
+				}
+			})
+		}
+
+		wg.Wait()
+
+		require.Equal(t, int64(999), counter.Load())
+	})
+}
+
+func Test_Counter_waiting(t *testing.T) {
+
+	t.Run("UpCounter WaitUntil() a reached target returns immediately", func(t *testing.T) {
+
+		counter := NewUpCounter(5)
+
+		require.Equal(t, int64(5), counter.WaitUntil(5))
+		require.Equal(t, int64(5), counter.WaitUntil(-1))
+	})
+
+	t.Run("UpCounter WaitUntil() wakes only those waiters whose target is reached", func(t *testing.T) {
+
+		counter := NewUpCounter(0)
+
+		var wg sync.WaitGroup
+		var woken [3]atomic.Int64
+
+		for i, target := range []int64{2, 4, 6} {
+
+			wg.Go(func() {
+
+				woken[i].Store(counter.WaitUntil(target))
+			})
+		}
+
+		for counter.Load() != 4 {
+
+			counter.Step()
+		}
+
+		deadline := time.Now().Add(time.Second)
+
+		for (woken[0].Load() == 0 || woken[1].Load() == 0) && time.Now().Before(deadline) {
+
+			time.Sleep(time.Millisecond)
+		}
+
+		require.GreaterOrEqual(t, woken[0].Load(), int64(2))
+		require.GreaterOrEqual(t, woken[1].Load(), int64(4))
+		require.Equal(t, int64(0), woken[2].Load())
+
+		counter.Step()
+		counter.Step()
+
+		wg.Wait()
+
+		require.Equal(t, int64(6), woken[2].Load())
+	})
+
+	t.Run("UpCounter WaitUntilContext() honours cancellation", func(t *testing.T) {
+
+		counter := NewUpCounter(0)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		defer cancel()
+
+		_, err := counter.WaitUntilContext(ctx, 10)
+
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		// the cancelled waiter has been removed, and is not notified
+		for i := 0; i != 10; i++ {
+
+			counter.Step()
+		}
+
+		count, err := counter.WaitUntilContext(context.Background(), 10)
+
+		require.Nil(t, err)
+		require.Equal(t, int64(10), count)
+	})
+
+	t.Run("UpCounter WaitUntil() is woken by Store()", func(t *testing.T) {
+
+		counter := NewUpCounter(0)
+
+		var wg sync.WaitGroup
+		var count int64
+
+		wg.Go(func() {
+
+			count = counter.WaitUntil(100)
+		})
+
+		counter.Store(1_000)
+
+		wg.Wait()
+
+		require.Equal(t, int64(1_000), count)
+	})
+
+	t.Run("UpCounter Changed()", func(t *testing.T) {
+
+		counter := NewUpCounter(0)
+
+		require.Equal(t, int64(0), <-counter.Changed(-1))
+
+		ch := counter.Changed(0)
+
+		select {
+		case <-ch:
+
+			require.Fail(t, "Changed() channel received a value before change")
+		default:
+		}
+
+		counter.Step()
+
+		require.Equal(t, int64(1), <-ch)
+	})
+
+	t.Run("DownCounter WaitDownTo() and WaitDownToContext()", func(t *testing.T) {
+
+		counter := NewDownCounter(10)
+
+		var wg sync.WaitGroup
+		var count int64
+
+		wg.Go(func() {
+
+			count = counter.WaitDownTo(0)
+		})
+
+		for i := 0; i != 10; i++ {
+
+			counter.Step()
+		}
+
+		wg.Wait()
+
+		require.Equal(t, int64(0), count)
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		cancel()
+
+		_, err := counter.WaitDownToContext(ctx, -1)
+
+		require.ErrorIs(t, err, context.Canceled)
+
+		count, err = counter.WaitDownToContext(ctx, 0)
+
+		require.Nil(t, err)
+		require.Equal(t, int64(0), count)
+	})
+
+	t.Run("DownCounter Changed()", func(t *testing.T) {
+
+		counter := NewDownCounter(3)
+
+		ch := counter.Changed(3)
+
+		counter.Step()
+
+		require.Equal(t, int64(2), <-ch)
+	})
+
+	t.Run("many waiters with many steppers", func(t *testing.T) {
+
+		const numSteppers = 8
+		const numSteps = 1_000
+		const numWaiters = 100
+
+		counter := NewUpCounter(0)
+
+		var waiters sync.WaitGroup
+
+		for i := 0; i != numWaiters; i++ {
+
+			target := int64(i * numSteppers * numSteps / numWaiters)
+
+			waiters.Go(func() {
+
+				count := counter.WaitUntil(target)
+
+				assert.GreaterOrEqual(t, count, target)
+			})
+		}
+
+		var steppers sync.WaitGroup
+
+		for i := 0; i != numSteppers; i++ {
+
+			steppers.Go(func() {
+
+				for j := 0; j != numSteps; j++ {
+
+					counter.Step()
+				}
+			})
+		}
+
+		steppers.Wait()
+		waiters.Wait()
+
+		require.Equal(t, int64(numSteppers*numSteps), counter.Load())
+	})
+}
+
+func Test_Counter_Values(t *testing.T) {
+
+	t.Run("UpCounter Values() yields the current value, then changes", func(t *testing.T) {
+
+		counter := NewUpCounter(10)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var values []int64
+
+		for count := range counter.Values(ctx) {
+
+			values = append(values, count)
+
+			if count == 13 {
+
+				break
+			}
+
+			counter.Step()
+		}
+
+		require.Equal(t, []int64{10, 11, 12, 13}, values)
+		require.Equal(t, 0, counter.NumWaiters())
+	})
+
+	t.Run("UpCounter Values() coalesces changes", func(t *testing.T) {
+
+		counter := NewUpCounter(0)
+
+		var values []int64
+
+		for count := range counter.Values(context.Background()) {
+
+			values = append(values, count)
+
+			if count == 100 {
+
+				break
+			}
+
+			// the consumer is slow: all steps occur before the next value
+			for i := 0; i != 50; i++ {
+
+				counter.Step()
+			}
+		}
+
+		require.Equal(t, []int64{0, 50, 100}, values)
+	})
+
+	t.Run("DownCounter Values() ends when ctx is done", func(t *testing.T) {
+
+		counter := NewDownCounter(0)
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		var values []int64
+		var wg sync.WaitGroup
+
+		wg.Go(func() {
+
+			for count := range counter.Values(ctx) {
+
+				values = append(values, count)
+			}
+		})
+
+		for counter.NumWaiters() == 0 {
+
+			time.Sleep(time.Millisecond)
+		}
+
+		cancel()
+
+		wg.Wait()
+
+		require.Equal(t, []int64{0}, values)
+		require.Equal(t, 0, counter.NumWaiters())
+	})
+
+	t.Run("Values() with ctx already done yields nothing", func(t *testing.T) {
+
+		counter := NewUpCounter(0)
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		cancel()
+
+		for range counter.Values(ctx) {
+
+			require.Fail(t, "unexpected value")
+		}
+	})
+
+	t.Run("Values() observes concurrent steps to the final value", func(t *testing.T) {
+
+		const numSteppers = 4
+		const numSteps = 1_000
+
+		counter := NewUpCounter(0)
+
+		var steppers sync.WaitGroup
+
+		for i := 0; i != numSteppers; i++ {
+
+			steppers.Go(func() {
+
+				for j := 0; j != numSteps; j++ {
+
+					counter.Step()
+				}
+			})
+		}
+
+		previous := int64(-1)
+
+		for count := range counter.Values(context.Background()) {
+
+			require.Greater(t, count, previous)
+
+			previous = count
+
+			if count == numSteppers*numSteps {
+
+				break
+			}
+		}
+
+		steppers.Wait()
+	})
+}
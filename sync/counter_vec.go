@@ -0,0 +1,278 @@
+// Copyright 2019-2026 Matthew Wilson and Synesis Information Systems. All
+// rights reserved. Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+ * Created: 19th October 2026
+ * Updated:
+ */
+
+// Definition of a labelled vector of counters.
+
+package sync
+
+import (
+	"encoding/binary"
+	"errors"
+	"iter"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// The label value of each label of the overflow counter of a CounterVec.
+const CounterVecOverflowValue = "__overflow__"
+
+var (
+	errCounterVecMustHaveLabels     = errors.New("counter vector must have at least one label")
+	errCounterVecLabelCountMismatch = errors.New("number of label values does not match the number of labels")
+)
+
+// The value of one counter of a CounterVec, as obtained by Snapshot().
+type CounterVecSample struct {
+	// The label values, in the order of the vector's label names.
+	Labels []string
+	// The value of the counter.
+	Value int64
+}
+
+type _counterVecEntry struct {
+	labels  []string
+	counter UpCounter
+}
+
+// A vector of UpCounter instances, each identified by a tuple of label
+// values (such as an endpoint and a status), that may be operated safely
+// by multiple concurrent goroutines.
+//
+// Counters are created on first use. Obtaining the counter of an existing
+// label tuple takes no lock. The number of distinct label tuples may be
+// limited, in which case tuples beyond the limit share a single overflow
+// counter, whose label values are all CounterVecOverflowValue.
+type CounterVec struct {
+	labelNames []string
+	limit      int64
+	entries    sync.Map // of string -> *_counterVecEntry
+	size       int64
+	overflow   _counterVecEntry
+}
+
+// Creates a new CounterVec with the given label names, holding at most
+// limit distinct label tuples (other than the overflow tuple); a limit of
+// zero or less indicates no limit.
+//
+// Preconditions:
+// - len(labelNames) > 0;
+func NewCounterVec(limit int, labelNames ...string) *CounterVec {
+
+	if len(labelNames) == 0 {
+
+		panic(errCounterVecMustHaveLabels)
+	}
+
+	overflowLabels := make([]string, len(labelNames))
+
+	for i := range overflowLabels {
+
+		overflowLabels[i] = CounterVecOverflowValue
+	}
+
+	return &CounterVec{
+		labelNames: slices.Clone(labelNames),
+		limit:      int64(limit),
+		overflow: _counterVecEntry{
+			labels:  overflowLabels,
+			counter: NewUpCounter(0),
+		},
+	}
+}
+
+// Obtains the key of a tuple of label values. Each value but the last is
+// preceded by its length, so that distinct tuples have distinct keys
+// whatever the bytes of their values.
+func counterVecKey(labelValues []string) string {
+
+	if len(labelValues) == 1 {
+
+		return labelValues[0]
+	}
+
+	last := len(labelValues) - 1
+
+	var key []byte
+
+	for _, value := range labelValues[:last] {
+
+		key = binary.AppendUvarint(key, uint64(len(value)))
+		key = append(key, value...)
+	}
+
+	key = append(key, labelValues[last]...)
+
+	return string(key)
+}
+
+// Obtains the counter for the given label values, creating it if
+// necessary. If the vector is at its limit and has no counter for the
+// label values, the overflow counter is obtained.
+//
+// Preconditions:
+// - len(labelValues) == len(LabelNames());
+func (v *CounterVec) With(labelValues ...string) *UpCounter {
+
+	if len(labelValues) != len(v.labelNames) {
+
+		panic(errCounterVecLabelCountMismatch)
+	}
+
+	key := counterVecKey(labelValues)
+
+	if e, ok := v.entries.Load(key); ok {
+
+		return &e.(*_counterVecEntry).counter
+	}
+
+	// reserve a place before creating, so that the limit is never exceeded
+	if size := atomic.AddInt64(&v.size, 1); v.limit > 0 && size > v.limit {
+
+		atomic.AddInt64(&v.size, -1)
+
+		// another goroutine may have created it in the meantime
+		if e, ok := v.entries.Load(key); ok {
+
+			return &e.(*_counterVecEntry).counter
+		}
+
+		return &v.overflow.counter
+	}
+
+	e, loaded := v.entries.LoadOrStore(key, &_counterVecEntry{
+		labels:  slices.Clone(labelValues),
+		counter: NewUpCounter(0),
+	})
+
+	if loaded {
+
+		atomic.AddInt64(&v.size, -1)
+	}
+
+	return &e.(*_counterVecEntry).counter
+}
+
+// Increments the counter for the given label values; equivalent to
+// With(labelValues...).Step().
+//
+// Returns:
+// the new value of the counter
+func (v *CounterVec) Step(labelValues ...string) (newCount int64) {
+
+	return v.With(labelValues...).Step()
+}
+
+// Obtains the label names of the vector.
+func (v *CounterVec) LabelNames() []string {
+
+	return slices.Clone(v.labelNames)
+}
+
+// Obtains the number of distinct label tuples, not including the overflow
+// tuple.
+func (v *CounterVec) Len() int {
+
+	return int(atomic.LoadInt64(&v.size))
+}
+
+// Obtains the overflow counter, which counts for all label tuples beyond
+// the limit.
+func (v *CounterVec) Overflow() *UpCounter {
+
+	return &v.overflow.counter
+}
+
+// Obtains a sequence of the label values and current value of each
+// counter, in no particular order, followed by those of the overflow
+// counter if it is non-zero. The slices of label values must not be
+// modified.
+func (v *CounterVec) All() iter.Seq2[[]string, int64] {
+
+	return func(yield func([]string, int64) bool) {
+
+		proceed := true
+
+		v.entries.Range(func(_, value any) bool {
+
+			e := value.(*_counterVecEntry)
+
+			proceed = yield(e.labels, e.counter.Load())
+
+			return proceed
+		})
+
+		if proceed {
+
+			if count := v.overflow.counter.Load(); count != 0 {
+
+				yield(v.overflow.labels, count)
+			}
+		}
+	}
+}
+
+func (v *CounterVec) snapshot(reset bool) (samples []CounterVecSample) {
+
+	add := func(e *_counterVecEntry) {
+
+		var count int64
+
+		if reset {
+
+			count, _ = e.counter.LoadAndReset()
+		} else {
+
+			count = e.counter.Load()
+		}
+
+		samples = append(samples, CounterVecSample{
+			Labels: slices.Clone(e.labels),
+			Value:  count,
+		})
+	}
+
+	v.entries.Range(func(_, value any) bool {
+
+		add(value.(*_counterVecEntry))
+
+		return true
+	})
+
+	slices.SortFunc(samples, func(a, b CounterVecSample) int {
+
+		return slices.Compare(a.Labels, b.Labels)
+	})
+
+	if v.overflow.counter.Load() != 0 {
+
+		add(&v.overflow)
+	}
+
+	return
+}
+
+// Obtains the label values and value of each counter, sorted by label
+// values, followed by those of the overflow counter if it is non-zero.
+//
+// Each value is obtained atomically, but the snapshot as a whole is not a
+// single atomic observation of the vector.
+func (v *CounterVec) Snapshot() []CounterVecSample {
+
+	return v.snapshot(false)
+}
+
+// As Snapshot(), but also resets each counter to zero, each counter's
+// value being obtained and reset as a single atomic operation so that no
+// step is lost; useful for periodic reporting. The label tuples are
+// retained.
+func (v *CounterVec) SnapshotAndReset() []CounterVecSample {
+
+	return v.snapshot(true)
+}
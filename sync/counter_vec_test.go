@@ -0,0 +1,192 @@
+package sync_test
+
+import (
+	. "github.com/synesissoftware/syngo/sync"
+
+	"github.com/stretchr/testify/require"
+
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func Test_CounterVec(t *testing.T) {
+
+	t.Run("NewCounterVec() with no labels panics", func(t *testing.T) {
+
+		require.Panics(t, func() {
+
+			_ = NewCounterVec(0)
+		})
+	})
+
+	t.Run("With() creates counters lazily and returns the same counter", func(t *testing.T) {
+
+		vec := NewCounterVec(0, "endpoint", "status")
+
+		require.Equal(t, 0, vec.Len())
+		require.Equal(t, []string{"endpoint", "status"}, vec.LabelNames())
+
+		c1 := vec.With("/a", "200")
+		c2 := vec.With("/a", "200")
+		c3 := vec.With("/a", "500")
+
+		require.Same(t, c1, c2)
+		require.NotSame(t, c1, c3)
+		require.Equal(t, 2, vec.Len())
+
+		require.Equal(t, int64(1), c1.Step())
+		require.Equal(t, int64(2), vec.Step("/a", "200"))
+		require.Equal(t, int64(0), c3.Load())
+	})
+
+	t.Run("label values that join alike are distinct", func(t *testing.T) {
+
+		vec := NewCounterVec(0, "a", "b")
+
+		require.NotSame(t, vec.With("x", "yz"), vec.With("xy", "z"))
+	})
+
+	t.Run("label values containing any bytes are distinct", func(t *testing.T) {
+
+		vec := NewCounterVec(0, "a", "b")
+
+		require.NotSame(t, vec.With("x\xffy", "z"), vec.With("x", "y\xffz"))
+		require.NotSame(t, vec.With("\x01x", ""), vec.With("", "\x01x"))
+		require.NotSame(t, vec.With("\x01", "xy"), vec.With("", "\x01xy"))
+		require.Same(t, vec.With("x\xffy", "z"), vec.With("x\xffy", "z"))
+	})
+
+	t.Run("With() with the wrong number of label values panics", func(t *testing.T) {
+
+		vec := NewCounterVec(0, "endpoint", "status")
+
+		require.Panics(t, func() {
+
+			_ = vec.With("/a")
+		})
+	})
+
+	t.Run("label tuples beyond the limit share the overflow counter", func(t *testing.T) {
+
+		vec := NewCounterVec(2, "user")
+
+		vec.Step("alice")
+		vec.Step("bob")
+		vec.Step("carol")
+		vec.Step("dave")
+		vec.Step("alice")
+
+		require.Equal(t, 2, vec.Len())
+		require.Same(t, vec.Overflow(), vec.With("erin"))
+		require.Equal(t, int64(2), vec.Overflow().Load())
+
+		require.Equal(t, []CounterVecSample{
+			{Labels: []string{"alice"}, Value: 2},
+			{Labels: []string{"bob"}, Value: 1},
+			{Labels: []string{CounterVecOverflowValue}, Value: 2},
+		}, vec.Snapshot())
+	})
+
+	t.Run("All() yields each counter, then the overflow counter", func(t *testing.T) {
+
+		vec := NewCounterVec(1, "a", "b")
+
+		vec.Step("x", "1")
+		vec.Step("y", "2")
+
+		seen := map[string]int64{}
+
+		for labels, count := range vec.All() {
+
+			seen[fmt.Sprint(labels)] = count
+		}
+
+		require.Equal(t, map[string]int64{
+			"[x 1]":                       1,
+			"[__overflow__ __overflow__]": 1,
+		}, seen)
+
+		n := 0
+
+		for range vec.All() {
+
+			n++
+
+			break
+		}
+
+		require.Equal(t, 1, n)
+	})
+
+	t.Run("SnapshotAndReset() resets the counters but retains the label tuples", func(t *testing.T) {
+
+		vec := NewCounterVec(0, "status")
+
+		vec.Step("500")
+		vec.Step("200")
+		vec.Step("200")
+
+		require.Equal(t, []CounterVecSample{
+			{Labels: []string{"200"}, Value: 2},
+			{Labels: []string{"500"}, Value: 1},
+		}, vec.SnapshotAndReset())
+
+		vec.Step("200")
+
+		require.Equal(t, []CounterVecSample{
+			{Labels: []string{"200"}, Value: 1},
+			{Labels: []string{"500"}, Value: 0},
+		}, vec.Snapshot())
+	})
+
+	t.Run("concurrent creation respects the limit and loses no steps", func(t *testing.T) {
+
+		const numGoroutines = 8
+		const numSteps = 1_000
+		const numLabels = 20
+
+		vec := NewCounterVec(10, "label")
+
+		var wg sync.WaitGroup
+
+		for range numGoroutines {
+
+			wg.Go(func() {
+
+				for j := range numSteps {
+
+					vec.Step(fmt.Sprint(j % numLabels))
+				}
+			})
+		}
+
+		wg.Wait()
+
+		require.Equal(t, 10, vec.Len())
+
+		var total int64
+
+		for _, sample := range vec.Snapshot() {
+
+			total += sample.Value
+		}
+
+		require.Equal(t, int64(numGoroutines*numSteps), total)
+	})
+}
+
+func Benchmark_CounterVec_Step_existing(b *testing.B) {
+
+	vec := NewCounterVec(0, "endpoint", "status")
+
+	vec.Step("/api", "200")
+
+	b.RunParallel(func(pb *testing.PB) {
+
+		for pb.Next() {
+
+			vec.Step("/api", "200")
+		}
+	})
+}
@@ -0,0 +1,195 @@
+// Copyright 2019-2026 Matthew Wilson and Synesis Information Systems. All
+// rights reserved. Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+ * Created: 19th October 2026
+ * Updated:
+ */
+
+// Definition of a one-way switch that carries the error that set it.
+
+package sync
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+var (
+	// The error recorded by ErrorLatch.Set() when it is passed a nil error,
+	// as context.Canceled is by a context.CancelCauseFunc.
+	ErrErrorLatchSet = errors.New("error latch set")
+)
+
+type _errorLatchCause struct {
+	errs []error // never modified once published
+	err  error
+}
+
+// A one-way switch, as BoolLatch, that records the error that set it -
+// such as the reason for a shutdown - and that may be operated safely by
+// multiple concurrent goroutines.
+//
+// The first error wins: it is the one obtained by Err() unless the latch
+// accumulates, in which case errors from subsequent calls to Set() are
+// joined to it, in the order of the calls.
+type ErrorLatch struct {
+	_latchNotifier
+	_observed
+	cause      atomic.Pointer[_errorLatchCause]
+	accumulate bool
+}
+
+// Creates a new ErrorLatch that records only the first error.
+func NewErrorLatch() ErrorLatch {
+
+	observeCreated("ErrorLatch", 0)
+
+	return ErrorLatch{}
+}
+
+// Creates a new ErrorLatch that records the first error and joins to it,
+// with errors.Join, the errors passed to subsequent calls to Set().
+func NewAccumulatingErrorLatch() ErrorLatch {
+
+	observeCreated("ErrorLatch", 0)
+
+	return ErrorLatch{
+		accumulate: true,
+	}
+}
+
+// Sets the instance to the latched state, recording err, if it is not
+// currently latched; if it is, err is joined to the recorded error if the
+// latch accumulates, and otherwise discarded. If err is nil,
+// ErrErrorLatchSet is recorded in its place.
+//
+// The error is recorded before the latch's waiters are released, so that
+// Err() obtains it by the time that Done() is closed.
+//
+// Returns:
+// true if the latch was flipped; false otherwise (meaning it was already
+// latched)
+func (l *ErrorLatch) Set(err error) (flipped bool) {
+
+	flipped = l.set(err)
+
+	if o := l._observed.load(); o != nil {
+
+		observeStep(o, "ErrorLatch", l, flipped, true, 0)
+	}
+
+	return
+}
+
+func (l *ErrorLatch) set(err error) (flipped bool) {
+
+	if err == nil {
+
+		err = ErrErrorLatchSet
+	}
+
+	if l.cause.CompareAndSwap(nil, &_errorLatchCause{
+		errs: []error{err},
+		err:  err,
+	}) {
+
+		flipped = true
+
+		l._latchNotifier.fire()
+
+		return
+	}
+
+	if !l.accumulate {
+
+		return
+	}
+
+	for {
+
+		old := l.cause.Load()
+
+		errs := append(old.errs[:len(old.errs):len(old.errs)], err)
+
+		if l.cause.CompareAndSwap(old, &_errorLatchCause{
+			errs: errs,
+			err:  errors.Join(errs...),
+		}) {
+
+			return
+		}
+	}
+}
+
+// Obtains the recorded error, or nil if the latch is not latched.
+//
+// If the latch accumulates and has been set more than once, the error is
+// the errors.Join of all those recorded; otherwise, it is the error passed
+// to the call to Set() that flipped the latch.
+func (l *ErrorLatch) Err() error {
+
+	if c := l.cause.Load(); c != nil {
+
+		return c.err
+	}
+
+	return nil
+}
+
+// Indicates whether the latch has latched, without changing its state.
+func (l *ErrorLatch) IsLatched() bool {
+
+	return l.cause.Load() != nil
+}
+
+// Obtains a channel that is closed when the latch latches.
+func (l *ErrorLatch) Done() <-chan struct{} {
+
+	return l._latchNotifier.done()
+}
+
+// Blocks the caller until the latch latches, after which the recorded
+// error may be obtained by Err().
+func (l *ErrorLatch) Wait() {
+
+	if o := l._observed.load(); o != nil {
+
+		observeWait(o, "ErrorLatch", l, &l._latchNotifier, nil)
+
+		return
+	}
+
+	l._latchNotifier.wait()
+}
+
+// Blocks the caller until the latch latches or ctx is done.
+//
+// Returns:
+// nil if the latch latched (in which case the recorded error may be
+// obtained by Err()); ctx.Err() otherwise
+func (l *ErrorLatch) WaitContext(ctx context.Context) error {
+
+	if o := l._observed.load(); o != nil {
+
+		return observeWait(o, "ErrorLatch", l, &l._latchNotifier, ctx)
+	}
+
+	return l._latchNotifier.waitContext(ctx)
+}
+
+// Installs o as the observer of the instance, in place of any global
+// observer. Passing nil reverts to the global observer.
+func (l *ErrorLatch) SetObserver(o Observer) {
+
+	l._observed.set(o)
+}
+
+// Obtains the number of goroutines currently blocked in Wait() or
+// WaitContext().
+func (l *ErrorLatch) NumWaiters() int {
+
+	return int(l._latchNotifier.numWaiters())
+}
@@ -0,0 +1,174 @@
+package sync_test
+
+import (
+	. "github.com/synesissoftware/syngo/sync"
+
+	"github.com/synesissoftware/syngo/sync/synctesting"
+
+	"github.com/stretchr/testify/require"
+
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_ErrorLatch(t *testing.T) {
+
+	t.Run("a new latch is not latched and has no error", func(t *testing.T) {
+
+		latch := NewErrorLatch()
+
+		require.False(t, latch.IsLatched())
+		require.Nil(t, latch.Err())
+	})
+
+	t.Run("the first error wins", func(t *testing.T) {
+
+		latch := NewErrorLatch()
+
+		first := errors.New("first")
+		second := errors.New("second")
+
+		require.True(t, latch.Set(first))
+		require.False(t, latch.Set(second))
+
+		require.True(t, latch.IsLatched())
+		require.Same(t, first, latch.Err())
+	})
+
+	t.Run("a nil error is recorded as ErrErrorLatchSet", func(t *testing.T) {
+
+		latch := NewErrorLatch()
+
+		require.True(t, latch.Set(nil))
+		require.ErrorIs(t, latch.Err(), ErrErrorLatchSet)
+	})
+
+	t.Run("an accumulating latch joins subsequent errors", func(t *testing.T) {
+
+		latch := NewAccumulatingErrorLatch()
+
+		first := errors.New("first")
+		second := errors.New("second")
+
+		require.True(t, latch.Set(first))
+		require.Same(t, first, latch.Err())
+
+		require.False(t, latch.Set(second))
+		require.False(t, latch.Set(nil))
+
+		err := latch.Err()
+
+		require.ErrorIs(t, err, first)
+		require.ErrorIs(t, err, second)
+		require.ErrorIs(t, err, ErrErrorLatchSet)
+		require.Equal(t, "first\nsecond\nerror latch set", err.Error())
+	})
+
+	t.Run("the error is available once Done() is closed", func(t *testing.T) {
+
+		latch := NewErrorLatch()
+
+		reason := errors.New("shutting down")
+
+		go latch.Set(reason)
+
+		<-latch.Done()
+
+		require.Same(t, reason, latch.Err())
+	})
+
+	t.Run("WaitContext() returns the context's error if not latched", func(t *testing.T) {
+
+		latch := NewErrorLatch()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		require.ErrorIs(t, latch.WaitContext(ctx), context.DeadlineExceeded)
+		require.Nil(t, latch.Err())
+	})
+
+	t.Run("concurrent Set() flips once and accumulates every error", func(t *testing.T) {
+
+		synctesting.VerifyNoLeaks(t)
+
+		const numWorkers = 16
+
+		latch := NewAccumulatingErrorLatch()
+
+		errs := make([]error, numWorkers)
+
+		for i := range errs {
+
+			errs[i] = fmt.Errorf("worker %d failed", i)
+		}
+
+		var numFlips int64
+
+		var wg sync.WaitGroup
+
+		for i := range numWorkers {
+
+			wg.Go(func() {
+
+				if latch.Set(errs[i]) {
+
+					atomic.AddInt64(&numFlips, 1)
+				}
+			})
+		}
+
+		latch.Wait()
+
+		wg.Wait()
+
+		require.Equal(t, int64(1), numFlips)
+
+		for _, err := range errs {
+
+			require.ErrorIs(t, latch.Err(), err)
+		}
+	})
+
+	t.Run("per-instance observer", func(t *testing.T) {
+
+		var observer recordingObserver
+
+		latch := NewAccumulatingErrorLatch()
+
+		latch.SetObserver(&observer)
+
+		latch.Set(errors.New("first"))
+		latch.Set(errors.New("second"))
+		latch.Wait()
+
+		require.Equal(t, []LatchEventKind{LatchFlipped, LatchSteppedAfterLatched, LatchWaited}, observer.kinds())
+		require.Equal(t, "ErrorLatch", observer.events[0].Type)
+		require.Same(t, &latch, observer.events[0].Latch)
+	})
+
+	t.Run("global observer", func(t *testing.T) {
+
+		var global recordingObserver
+
+		previous := SetObserver(&global)
+
+		defer SetObserver(previous)
+
+		latch := NewErrorLatch()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		require.ErrorIs(t, latch.WaitContext(ctx), context.DeadlineExceeded)
+
+		require.Equal(t, []LatchEventKind{LatchCreated, LatchTimedOut}, global.kinds())
+		require.Equal(t, "ErrorLatch", global.events[0].Type)
+		require.ErrorIs(t, global.events[1].Err, context.DeadlineExceeded)
+	})
+}
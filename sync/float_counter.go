@@ -0,0 +1,273 @@
+// Copyright 2019-2026 Matthew Wilson and Synesis Information Systems. All
+// rights reserved. Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+ * Created: 19th October 2026
+ * Updated:
+ */
+
+// Definition of floating-point accumulator counters.
+
+package sync
+
+import (
+	"math"
+	"math/bits"
+	"math/rand/v2"
+	"runtime"
+	"sync/atomic"
+	"unsafe"
+)
+
+// Adds v to the float64 whose bits are held in *p, by compare-and-swap.
+//
+// Returns:
+// the new value
+func addFloat64(p *uint64, v float64) (newValue float64) {
+
+	for {
+
+		old := atomic.LoadUint64(p)
+
+		newValue = math.Float64frombits(old) + v
+
+		if atomic.CompareAndSwapUint64(p, old, math.Float64bits(newValue)) {
+
+			return
+		}
+	}
+}
+
+// Obtains the rounding error of a + b, where sum is its rounded result,
+// exactly, regardless of the magnitudes of a and b (Knuth's TwoSum).
+func twoSumError(a, b, sum float64) float64 {
+
+	bPart := sum - a
+	aPart := sum - bPart
+
+	return (a - aPart) + (b - bPart)
+}
+
+// An accumulator of float64 values that may be operated safely, and
+// without locks, by multiple concurrent goroutines.
+//
+// The value is held as the bits of a float64, changed by compare-and-swap.
+// A compensated counter additionally accumulates the rounding error of each
+// addition - obtained exactly, since the compare-and-swap fixes the
+// operands - and adds it back in on Load(), so that the accumulation of
+// many small values into a large total loses little accuracy. The value
+// and the compensation are separate words, so a Load() concurrent with an
+// Add() may include the one's effect on the value but not yet on the
+// compensation.
+//
+// The zero value is an uncompensated counter whose value is 0.
+type FloatCounter struct {
+	value        uint64 // float64 bits
+	compensation uint64 // float64 bits
+	initial      float64
+	compensated  bool
+}
+
+// Creates a new FloatCounter.
+func NewFloatCounter(initialValue float64) FloatCounter {
+
+	return FloatCounter{
+		value:   math.Float64bits(initialValue),
+		initial: initialValue,
+	}
+}
+
+// Creates a new compensated FloatCounter, which is more accurate, but
+// somewhat more expensive, than an uncompensated one.
+func NewCompensatedFloatCounter(initialValue float64) FloatCounter {
+
+	return FloatCounter{
+		value:       math.Float64bits(initialValue),
+		initial:     initialValue,
+		compensated: true,
+	}
+}
+
+// Adds delta to the counter.
+//
+// Returns:
+// the new value of the counter
+func (c *FloatCounter) Add(delta float64) (newValue float64) {
+
+	if !c.compensated {
+
+		return addFloat64(&c.value, delta)
+	}
+
+	for {
+
+		old := atomic.LoadUint64(&c.value)
+		sum := math.Float64frombits(old)
+
+		newValue = sum + delta
+
+		if atomic.CompareAndSwapUint64(&c.value, old, math.Float64bits(newValue)) {
+
+			// an infinite operand leaves a NaN error, which is discarded
+			if err := twoSumError(sum, delta, newValue); err != 0 && !math.IsNaN(err) {
+
+				return newValue + addFloat64(&c.compensation, err)
+			}
+
+			return newValue + math.Float64frombits(atomic.LoadUint64(&c.compensation))
+		}
+	}
+}
+
+// Obtains the current value of the counter.
+func (c *FloatCounter) Load() (value float64) {
+
+	value = math.Float64frombits(atomic.LoadUint64(&c.value))
+
+	if c.compensated {
+
+		value += math.Float64frombits(atomic.LoadUint64(&c.compensation))
+	}
+
+	return
+}
+
+// Obtains the current value of the counter and resets it to its initial
+// value; useful for periodic reporting.
+//
+// The value is obtained and reset as a single atomic operation, and then
+// the compensation, if any, likewise; so a compensated counter may
+// attribute the (small) rounding error of a concurrent Add() to the
+// following interval.
+func (c *FloatCounter) LoadAndReset() (value float64) {
+
+	value = math.Float64frombits(atomic.SwapUint64(&c.value, math.Float64bits(c.initial)))
+
+	if c.compensated {
+
+		value += math.Float64frombits(atomic.SwapUint64(&c.compensation, 0))
+	}
+
+	return
+}
+
+// The size of the padded shards of a ShardedFloatCounter, being that of two
+// cache lines, since some processors prefetch adjacent pairs.
+const floatCounterShardSize = 128
+
+type _floatCounterShard struct {
+	FloatCounter
+	_ [floatCounterShardSize - unsafe.Sizeof(FloatCounter{})]byte
+}
+
+// An accumulator of float64 values, as FloatCounter, that is spread across
+// a number of shards, each on its own cache lines, to reduce contention
+// between goroutines that add to it concurrently.
+//
+// Each Add() is made to a randomly chosen shard, and Load() obtains the
+// sum of the shards, so Add() is cheaper, and Load() more expensive, than
+// those of FloatCounter. Since the shards are read in turn, Load() is not
+// a single atomic observation of the counter.
+type ShardedFloatCounter struct {
+	shards      []_floatCounterShard
+	mask        uint64
+	compensated bool
+}
+
+// Creates a new ShardedFloatCounter with the given number of shards,
+// rounded up to a power of two; if shards is not positive, the number is
+// runtime.GOMAXPROCS(0), rounded up to a power of two. If compensated is
+// true, each shard is a compensated counter, and the shards are summed with
+// compensation.
+func NewShardedFloatCounter(initialValue float64, shards int, compensated bool) *ShardedFloatCounter {
+
+	if shards <= 0 {
+
+		shards = runtime.GOMAXPROCS(0)
+	}
+
+	n := 1 << bits.Len(uint(shards-1))
+
+	c := &ShardedFloatCounter{
+		shards:      make([]_floatCounterShard, n),
+		mask:        uint64(n - 1),
+		compensated: compensated,
+	}
+
+	for i := range c.shards {
+
+		v := 0.0
+
+		if i == 0 {
+
+			v = initialValue
+		}
+
+		if compensated {
+
+			c.shards[i].FloatCounter = NewCompensatedFloatCounter(v)
+		} else {
+
+			c.shards[i].FloatCounter = NewFloatCounter(v)
+		}
+	}
+
+	return c
+}
+
+// Obtains the number of shards.
+func (c *ShardedFloatCounter) NumShards() int {
+
+	return len(c.shards)
+}
+
+// Adds delta to the counter.
+func (c *ShardedFloatCounter) Add(delta float64) {
+
+	_ = c.shards[rand.Uint64()&c.mask].Add(delta)
+}
+
+func (c *ShardedFloatCounter) sum(load func(fc *FloatCounter) float64) (total float64) {
+
+	var compensation float64
+
+	for i := range c.shards {
+
+		v := load(&c.shards[i].FloatCounter)
+
+		if c.compensated {
+
+			next := total + v
+
+			if err := twoSumError(total, v, next); !math.IsNaN(err) {
+
+				compensation += err
+			}
+
+			total = next
+		} else {
+
+			total += v
+		}
+	}
+
+	total += compensation
+
+	return
+}
+
+// Obtains the current value of the counter, being the sum of its shards.
+func (c *ShardedFloatCounter) Load() float64 {
+
+	return c.sum((*FloatCounter).Load)
+}
+
+// Obtains the current value of the counter and resets it to its initial
+// value; useful for periodic reporting. Each shard is obtained and reset
+// as a single atomic operation, so no addition is lost, but an addition
+// made concurrently may be included in this interval or the next.
+func (c *ShardedFloatCounter) LoadAndReset() float64 {
+
+	return c.sum((*FloatCounter).LoadAndReset)
+}
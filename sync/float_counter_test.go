@@ -0,0 +1,201 @@
+package sync_test
+
+import (
+	. "github.com/synesissoftware/syngo/sync"
+
+	"github.com/stretchr/testify/require"
+
+	"math"
+	"sync"
+	"testing"
+)
+
+func Test_FloatCounter(t *testing.T) {
+
+	t.Run("the zero value is usable", func(t *testing.T) {
+
+		var c FloatCounter
+
+		require.Equal(t, 0.0, c.Load())
+		require.Equal(t, 1.5, c.Add(1.5))
+	})
+
+	t.Run("Add() and Load()", func(t *testing.T) {
+
+		c := NewFloatCounter(10)
+
+		require.Equal(t, 12.5, c.Add(2.5))
+		require.Equal(t, 12.0, c.Add(-0.5))
+		require.Equal(t, 12.0, c.Load())
+	})
+
+	t.Run("LoadAndReset() resets to the initial value", func(t *testing.T) {
+
+		c := NewCompensatedFloatCounter(1)
+
+		c.Add(2)
+
+		require.Equal(t, 3.0, c.LoadAndReset())
+		require.Equal(t, 1.0, c.Load())
+	})
+
+	t.Run("compensation retains small values added to a large total", func(t *testing.T) {
+
+		const n = 10_000
+
+		plain := NewFloatCounter(1e16)
+		compensated := NewCompensatedFloatCounter(1e16)
+
+		for range n {
+
+			plain.Add(1)
+			compensated.Add(1)
+		}
+
+		require.Equal(t, 1e16, plain.Load())
+		require.Equal(t, 1e16+n, compensated.Load())
+	})
+
+	t.Run("compensation tolerates infinities", func(t *testing.T) {
+
+		c := NewCompensatedFloatCounter(0)
+
+		c.Add(math.Inf(1))
+		c.Add(1)
+
+		require.True(t, math.IsInf(c.Load(), 1))
+	})
+
+	t.Run("concurrent Add() loses no values", func(t *testing.T) {
+
+		const numGoroutines = 8
+		const numAdds = 1_000
+
+		plain := NewFloatCounter(0)
+		compensated := NewCompensatedFloatCounter(0)
+
+		for _, c := range []*FloatCounter{&plain, &compensated} {
+
+			var wg sync.WaitGroup
+
+			for range numGoroutines {
+
+				wg.Go(func() {
+
+					for range numAdds {
+
+						c.Add(0.5)
+					}
+				})
+			}
+
+			wg.Wait()
+
+			require.Equal(t, float64(numGoroutines*numAdds)/2, c.Load())
+		}
+	})
+}
+
+func Test_ShardedFloatCounter(t *testing.T) {
+
+	t.Run("the number of shards is rounded up to a power of two", func(t *testing.T) {
+
+		require.Equal(t, 1, NewShardedFloatCounter(0, 1, false).NumShards())
+		require.Equal(t, 4, NewShardedFloatCounter(0, 3, false).NumShards())
+		require.Equal(t, 8, NewShardedFloatCounter(0, 8, false).NumShards())
+		require.LessOrEqual(t, 1, NewShardedFloatCounter(0, 0, false).NumShards())
+	})
+
+	t.Run("Load() and LoadAndReset() sum the shards", func(t *testing.T) {
+
+		c := NewShardedFloatCounter(100, 4, false)
+
+		for range 100 {
+
+			c.Add(0.25)
+		}
+
+		require.Equal(t, 125.0, c.Load())
+		require.Equal(t, 125.0, c.LoadAndReset())
+		require.Equal(t, 100.0, c.Load())
+	})
+
+	t.Run("compensated shards retain small values", func(t *testing.T) {
+
+		c := NewShardedFloatCounter(1e16, 4, true)
+
+		for range 1_000 {
+
+			c.Add(1)
+		}
+
+		require.Equal(t, 1e16+1_000, c.Load())
+	})
+
+	t.Run("concurrent Add() loses no values", func(t *testing.T) {
+
+		const numGoroutines = 8
+		const numAdds = 1_000
+
+		c := NewShardedFloatCounter(0, 0, false)
+
+		var wg sync.WaitGroup
+
+		for range numGoroutines {
+
+			wg.Go(func() {
+
+				for range numAdds {
+
+					c.Add(2)
+				}
+			})
+		}
+
+		wg.Wait()
+
+		require.Equal(t, float64(numGoroutines*numAdds*2), c.Load())
+	})
+}
+
+func Benchmark_FloatCounter_Add(b *testing.B) {
+
+	b.Run("uncompensated", func(b *testing.B) {
+
+		c := NewFloatCounter(0)
+
+		b.RunParallel(func(pb *testing.PB) {
+
+			for pb.Next() {
+
+				c.Add(1.5)
+			}
+		})
+	})
+
+	b.Run("compensated", func(b *testing.B) {
+
+		c := NewCompensatedFloatCounter(0)
+
+		b.RunParallel(func(pb *testing.PB) {
+
+			for pb.Next() {
+
+				c.Add(1.5)
+			}
+		})
+	})
+
+	b.Run("sharded", func(b *testing.B) {
+
+		c := NewShardedFloatCounter(0, 0, false)
+
+		b.RunParallel(func(pb *testing.PB) {
+
+			for pb.Next() {
+
+				c.Add(1.5)
+			}
+		})
+	})
+}
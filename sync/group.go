@@ -0,0 +1,232 @@
+// Copyright 2019-2026 Matthew Wilson and Synesis Information Systems. All
+// rights reserved. Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+ * Created: 19th October 2026
+ * Updated:
+ */
+
+// Definition of a task group with first-error cancellation.
+
+package sync
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var (
+	errGroupLimitModifiedWhileActive = errors.New("group limit may not be modified while tasks are active")
+)
+
+// A collection of goroutines working on subtasks of a common task, in the
+// manner of errgroup.Group.
+//
+// The first task to return a non-nil error sets the group's error latch
+// (see Failed()) and cancels the context obtained from NewGroup(), with
+// that error as its cause. Wait() returns once all tasks have completed,
+// with either the first error or, if SetJoinErrors(true) has been called,
+// all errors combined by errors.Join.
+//
+// A zero Group is valid, has no limit on active tasks, and does not cancel
+// on error.
+type Group struct {
+	cancel     context.CancelCauseFunc
+	wg         sync.WaitGroup
+	pending    _baseCounter
+	failed     BoolLatch
+	sem        chan struct{}
+	mx         sync.Mutex
+	errs       []error
+	joinErrors bool
+	waiting    _waiterCount
+}
+
+// Creates a new Group, along with a context derived from parent that is
+// cancelled when the first task fails or when Wait() returns, whichever
+// occurs first.
+func NewGroup(parent context.Context) (*Group, context.Context) {
+
+	ctx, cancel := context.WithCancelCause(parent)
+
+	return &Group{
+		cancel: cancel,
+	}, ctx
+}
+
+// Limits the number of active tasks in the group to at most n. A negative
+// value indicates no limit; a limit of zero prevents any new tasks.
+//
+// Preconditions:
+// - no tasks are active in the group;
+func (g *Group) SetLimit(n int) {
+
+	if 0 != g.pending.load() {
+
+		panic(errGroupLimitModifiedWhileActive)
+	}
+
+	if n < 0 {
+
+		g.sem = nil
+
+		return
+	}
+
+	g.sem = make(chan struct{}, n)
+}
+
+// Specifies whether Wait() returns all errors (combined by errors.Join)
+// rather than only the first.
+func (g *Group) SetJoinErrors(join bool) {
+
+	g.mx.Lock()
+	g.joinErrors = join
+	g.mx.Unlock()
+}
+
+// Calls fn in a new goroutine, blocking until it may do so without
+// exceeding the group's limit (if any).
+func (g *Group) Go(fn func() error) {
+
+	if g.sem != nil {
+
+		g.sem <- struct{}{}
+	}
+
+	g.start(fn)
+}
+
+// Calls fn in a new goroutine only if doing so would not exceed the
+// group's limit (if any).
+//
+// Returns:
+// true if fn was started; false otherwise
+func (g *Group) TryGo(fn func() error) bool {
+
+	if g.sem != nil {
+
+		select {
+		case g.sem <- struct{}{}:
+		default:
+
+			return false
+		}
+	}
+
+	g.start(fn)
+
+	return true
+}
+
+func (g *Group) start(fn func() error) {
+
+	g.pending.step(1)
+
+	g.wg.Add(1)
+
+	go func() {
+
+		defer g.done()
+
+		if err := fn(); err != nil {
+
+			g.fail(err)
+		}
+	}()
+}
+
+func (g *Group) done() {
+
+	if g.sem != nil {
+
+		<-g.sem
+	}
+
+	g.pending.step(-1)
+
+	g.wg.Done()
+}
+
+func (g *Group) fail(err error) {
+
+	g.mx.Lock()
+
+	first := len(g.errs) == 0
+
+	g.errs = append(g.errs, err)
+
+	g.mx.Unlock()
+
+	if first {
+
+		g.failed.Set()
+
+		if g.cancel != nil {
+
+			g.cancel(err)
+		}
+	}
+}
+
+// Blocks until all tasks started by Go() or TryGo() have completed.
+//
+// Returns:
+// nil if no task failed; otherwise, the first error or, if
+// SetJoinErrors(true) has been called, all errors combined by errors.Join
+func (g *Group) Wait() error {
+
+	if 0 != g.pending.load() {
+
+		token := g.waiting.begin()
+
+		g.wg.Wait()
+
+		g.waiting.end(token)
+	} else {
+
+		g.wg.Wait()
+	}
+
+	if g.cancel != nil {
+
+		g.cancel(context.Canceled)
+	}
+
+	g.mx.Lock()
+	defer g.mx.Unlock()
+
+	switch {
+	case len(g.errs) == 0:
+
+		return nil
+	case g.joinErrors:
+
+		return errors.Join(g.errs...)
+	default:
+
+		return g.errs[0]
+	}
+}
+
+// Obtains the number of tasks that have been started and not yet
+// completed.
+func (g *Group) Pending() int64 {
+
+	return g.pending.load()
+}
+
+// Obtains the group's error latch, which latches when the first task
+// fails.
+func (g *Group) Failed() Latch {
+
+	return &g.failed
+}
+
+// Obtains the number of goroutines currently blocked in Wait().
+func (g *Group) NumWaiters() int {
+
+	return int(g.waiting.load())
+}
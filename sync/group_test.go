@@ -0,0 +1,226 @@
+package sync_test
+
+import (
+	. "github.com/synesissoftware/syngo/sync"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_Group(t *testing.T) {
+
+	t.Run("Wait() with no tasks", func(t *testing.T) {
+
+		g, ctx := NewGroup(context.Background())
+
+		require.Nil(t, g.Wait())
+		require.ErrorIs(t, ctx.Err(), context.Canceled)
+		require.False(t, g.Failed().IsLatched())
+	})
+
+	t.Run("zero Group runs tasks", func(t *testing.T) {
+
+		var g Group
+		var count atomic.Int64
+
+		for i := 0; i != 100; i++ {
+
+			g.Go(func() error {
+
+				count.Add(1)
+
+				return nil
+			})
+		}
+
+		require.Nil(t, g.Wait())
+		require.Equal(t, int64(100), count.Load())
+		require.Equal(t, int64(0), g.Pending())
+	})
+
+	t.Run("first error cancels the context and is returned", func(t *testing.T) {
+
+		g, ctx := NewGroup(context.Background())
+
+		errFirst := errors.New("first")
+
+		g.Go(func() error {
+
+			return errFirst
+		})
+
+		g.Go(func() error {
+
+			<-ctx.Done()
+
+			return errors.New("second")
+		})
+
+		err := g.Wait()
+
+		require.ErrorIs(t, err, errFirst)
+		require.ErrorIs(t, context.Cause(ctx), errFirst)
+		require.True(t, g.Failed().IsLatched())
+	})
+
+	t.Run("SetJoinErrors(true) returns all errors", func(t *testing.T) {
+
+		g, ctx := NewGroup(context.Background())
+
+		g.SetJoinErrors(true)
+
+		errFirst := errors.New("first")
+		errSecond := errors.New("second")
+
+		g.Go(func() error {
+
+			return errFirst
+		})
+
+		g.Go(func() error {
+
+			<-ctx.Done()
+
+			return errSecond
+		})
+
+		g.Go(func() error {
+
+			return nil
+		})
+
+		err := g.Wait()
+
+		require.ErrorIs(t, err, errFirst)
+		require.ErrorIs(t, err, errSecond)
+		require.ErrorIs(t, context.Cause(ctx), errFirst)
+	})
+
+	t.Run("Failed() latch may be waited upon", func(t *testing.T) {
+
+		g, _ := NewGroup(context.Background())
+
+		release := NewBoolLatch()
+
+		g.Go(func() error {
+
+			release.Wait()
+
+			return errors.New("failed")
+		})
+
+		require.False(t, g.Failed().IsLatched())
+		require.Equal(t, int64(1), g.Pending())
+
+		release.Set()
+
+		<-g.Failed().Done()
+
+		require.NotNil(t, g.Wait())
+	})
+
+	t.Run("SetLimit() bounds the number of active tasks", func(t *testing.T) {
+
+		const limit = 3
+
+		g, _ := NewGroup(context.Background())
+
+		g.SetLimit(limit)
+
+		var active atomic.Int64
+		var maxActive atomic.Int64
+
+		for i := 0; i != 50; i++ {
+
+			g.Go(func() error {
+
+				n := active.Add(1)
+
+				for {
+					m := maxActive.Load()
+
+					if n <= m || maxActive.CompareAndSwap(m, n) {
+
+						break
+					}
+				}
+
+				time.Sleep(time.Millisecond)
+
+				active.Add(-1)
+
+				return nil
+			})
+		}
+
+		require.Nil(t, g.Wait())
+		assert.LessOrEqual(t, maxActive.Load(), int64(limit))
+		assert.Greater(t, maxActive.Load(), int64(0))
+	})
+
+	t.Run("TryGo() fails when at the limit", func(t *testing.T) {
+
+		g, _ := NewGroup(context.Background())
+
+		g.SetLimit(1)
+
+		release := NewBoolLatch()
+
+		require.True(t, g.TryGo(func() error {
+
+			release.Wait()
+
+			return nil
+		}))
+
+		require.False(t, g.TryGo(func() error {
+
+			return nil
+		}))
+
+		release.Set()
+
+		require.Nil(t, g.Wait())
+
+		require.True(t, g.TryGo(func() error {
+
+			return nil
+		}))
+
+		require.Nil(t, g.Wait())
+	})
+
+	t.Run("SetLimit() panics while tasks are active", func(t *testing.T) {
+
+		var g Group
+
+		release := NewBoolLatch()
+
+		g.Go(func() error {
+
+			release.Wait()
+
+			return nil
+		})
+
+		require.Panics(t, func() {
+
+			g.SetLimit(2)
+		})
+
+		require.Panics(t, func() {
+
+			g.SetLimit(-1)
+		})
+
+		release.Set()
+
+		require.Nil(t, g.Wait())
+	})
+}
@@ -0,0 +1,390 @@
+// Copyright 2019-2026 Matthew Wilson and Synesis Information Systems. All
+// rights reserved. Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+ * Created: 19th October 2026
+ * Updated:
+ */
+
+// Definition of a histogram with lock-free recording.
+
+package sync
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// Returned by HistogramSnapshot.Merge() when the snapshots have
+	// different bucket bounds.
+	ErrHistogramBoundsMismatch = errors.New("histogram bucket bounds do not match")
+
+	errHistogramMustHaveBounds            = errors.New("histogram must have at least one bucket bound")
+	errHistogramBoundsMustIncrease        = errors.New("histogram bucket bounds must be finite and strictly increasing")
+	errHistogramBucketCountMustBePositive = errors.New("histogram bucket count must be positive")
+	errHistogramExponentialParameters     = errors.New("exponential buckets require a positive start and a factor greater than 1")
+	errHistogramLinearWidthMustBePositive = errors.New("linear bucket width must be positive")
+	errHistogramQuantileOutOfRange        = errors.New("quantile must be in the range [0, 1]")
+)
+
+// Obtains count bucket bounds, the first being start and each subsequent
+// one being factor times its predecessor.
+//
+// Preconditions:
+// - start > 0;
+// - factor > 1;
+// - count > 0;
+func ExponentialBuckets(start, factor float64, count int) []float64 {
+
+	if count <= 0 {
+
+		panic(errHistogramBucketCountMustBePositive)
+	}
+
+	if !(start > 0) || !(factor > 1) {
+
+		panic(errHistogramExponentialParameters)
+	}
+
+	bounds := make([]float64, count)
+
+	for i := range bounds {
+
+		bounds[i] = start
+		start *= factor
+	}
+
+	return bounds
+}
+
+// Obtains count bucket bounds, the first being start and each subsequent
+// one being width greater than its predecessor.
+//
+// Preconditions:
+// - width > 0;
+// - count > 0;
+func LinearBuckets(start, width float64, count int) []float64 {
+
+	if count <= 0 {
+
+		panic(errHistogramBucketCountMustBePositive)
+	}
+
+	if !(width > 0) {
+
+		panic(errHistogramLinearWidthMustBePositive)
+	}
+
+	bounds := make([]float64, count)
+
+	for i := range bounds {
+
+		bounds[i] = start + float64(i)*width
+	}
+
+	return bounds
+}
+
+// A histogram of observed values, which may be recorded safely, and without
+// locks, by multiple concurrent goroutines.
+//
+// Each bucket counts the values that are less than or equal to its upper
+// bound and greater than the bound of the preceding bucket; a final bucket
+// counts the values greater than the last bound. Each bucket is an atomic
+// counter, and the sum of the values is held as the bits of a float64
+// changed by compare-and-swap.
+type Histogram struct {
+	bounds []float64
+	counts []int64 // len(bounds) + 1, the last being for values above all bounds
+	sum    uint64  // float64 bits
+}
+
+// Creates a new Histogram with the given bucket upper bounds, such as may
+// be obtained from ExponentialBuckets() or LinearBuckets().
+//
+// Preconditions:
+// - len(bounds) > 0;
+// - the bounds are finite and strictly increasing;
+func NewHistogram(bounds []float64) *Histogram {
+
+	if len(bounds) == 0 {
+
+		panic(errHistogramMustHaveBounds)
+	}
+
+	for i, bound := range bounds {
+
+		if math.IsInf(bound, 0) || math.IsNaN(bound) || (i != 0 && !(bound > bounds[i-1])) {
+
+			panic(errHistogramBoundsMustIncrease)
+		}
+	}
+
+	return &Histogram{
+		bounds: slices.Clone(bounds),
+		counts: make([]int64, len(bounds)+1),
+	}
+}
+
+// Records the value v. A NaN value is ignored.
+func (h *Histogram) Observe(v float64) {
+
+	if math.IsNaN(v) {
+
+		return
+	}
+
+	i := sort.SearchFloat64s(h.bounds, v)
+
+	atomic.AddInt64(&h.counts[i], 1)
+
+	_ = addFloat64(&h.sum, v)
+}
+
+// Records the duration d, in seconds.
+func (h *Histogram) ObserveDuration(d time.Duration) {
+
+	h.Observe(d.Seconds())
+}
+
+// Obtains the bucket upper bounds.
+func (h *Histogram) Bounds() []float64 {
+
+	return slices.Clone(h.bounds)
+}
+
+func (h *Histogram) snapshot(reset bool) (s HistogramSnapshot) {
+
+	s.Bounds = slices.Clone(h.bounds)
+	s.Counts = make([]int64, len(h.counts))
+
+	for i := range h.counts {
+
+		if reset {
+
+			s.Counts[i] = atomic.SwapInt64(&h.counts[i], 0)
+		} else {
+
+			s.Counts[i] = atomic.LoadInt64(&h.counts[i])
+		}
+
+		s.Count += s.Counts[i]
+	}
+
+	if reset {
+
+		s.Sum = math.Float64frombits(atomic.SwapUint64(&h.sum, 0))
+	} else {
+
+		s.Sum = math.Float64frombits(atomic.LoadUint64(&h.sum))
+	}
+
+	return
+}
+
+// Obtains a snapshot of the histogram.
+//
+// Each bucket is read atomically, but, since recording is lock-free, the
+// snapshot as a whole may include some but not all of the values being
+// recorded concurrently, and Sum may not exactly correspond to Counts.
+func (h *Histogram) Snapshot() HistogramSnapshot {
+
+	return h.snapshot(false)
+}
+
+// As Snapshot(), but also resets each bucket, and the sum, to zero, each
+// being read and reset as a single atomic operation so that no recorded
+// value is lost from the counts; useful for periodic reporting.
+func (h *Histogram) SnapshotAndReset() HistogramSnapshot {
+
+	return h.snapshot(true)
+}
+
+// Resets each bucket, and the sum, to zero.
+func (h *Histogram) Reset() {
+
+	_ = h.snapshot(true)
+}
+
+// A snapshot of the state of a Histogram, which may be merged with others
+// of the same bucket bounds and queried for quantiles.
+type HistogramSnapshot struct {
+	// The bucket upper bounds.
+	Bounds []float64
+	// The count of each bucket; there is one more than there are bounds,
+	// the last being the count of values greater than all bounds.
+	Counts []int64
+	// The total number of values recorded.
+	Count int64
+	// The sum of the values recorded.
+	Sum float64
+}
+
+// Obtains the mean of the values recorded, or NaN if none were.
+func (s HistogramSnapshot) Mean() float64 {
+
+	if s.Count == 0 {
+
+		return math.NaN()
+	}
+
+	return s.Sum / float64(s.Count)
+}
+
+// Combines the snapshot with other, such as the snapshot of a histogram of
+// another shard or another interval.
+//
+// Returns:
+// the combined snapshot, and nil; or an empty snapshot and
+// ErrHistogramBoundsMismatch if the snapshots' bounds differ
+func (s HistogramSnapshot) Merge(other HistogramSnapshot) (merged HistogramSnapshot, err error) {
+
+	if !slices.Equal(s.Bounds, other.Bounds) || len(s.Counts) != len(other.Counts) {
+
+		return HistogramSnapshot{}, ErrHistogramBoundsMismatch
+	}
+
+	merged.Bounds = slices.Clone(s.Bounds)
+	merged.Counts = make([]int64, len(s.Counts))
+
+	for i := range s.Counts {
+
+		merged.Counts[i] = s.Counts[i] + other.Counts[i]
+	}
+
+	merged.Count = s.Count + other.Count
+	merged.Sum = s.Sum + other.Sum
+
+	return
+}
+
+// Obtains an estimate of the q-quantile of the values recorded, by linear
+// interpolation within the bucket in which it falls, or NaN if no values
+// were recorded.
+//
+// The lower bound of the first bucket is taken to be 0 if its upper bound
+// is positive, and its upper bound otherwise. Values in the last bucket,
+// above all bounds, are estimated as the last bound.
+//
+// Preconditions:
+// - 0 <= q <= 1;
+func (s HistogramSnapshot) Quantile(q float64) float64 {
+
+	if !(q >= 0 && q <= 1) {
+
+		panic(errHistogramQuantileOutOfRange)
+	}
+
+	if s.Count == 0 {
+
+		return math.NaN()
+	}
+
+	rank := q * float64(s.Count)
+
+	var cumulative int64
+
+	for i, count := range s.Counts {
+
+		if count == 0 || float64(cumulative+count) < rank {
+
+			cumulative += count
+
+			continue
+		}
+
+		if i == len(s.Bounds) {
+
+			return s.Bounds[i-1]
+		}
+
+		upper := s.Bounds[i]
+
+		var lower float64
+
+		switch {
+		case i != 0:
+
+			lower = s.Bounds[i-1]
+		case upper > 0:
+
+			lower = 0
+		default:
+
+			return upper
+		}
+
+		return lower + (upper-lower)*(rank-float64(cumulative))/float64(count)
+	}
+
+	// only reached through rounding of rank
+	return s.Bounds[len(s.Bounds)-1]
+}
+
+// An Observer that records, in seconds, the durations of waits on latches
+// and the intervals between successive steps of each latch into
+// histograms.
+//
+// The time of each latch's last step is retained until the latch flips.
+type HistogramObserver struct {
+	waits     *Histogram
+	steps     *Histogram
+	clock     Clock
+	lastSteps sync.Map // of Latch -> time.Time
+}
+
+// Creates a new HistogramObserver that records wait durations (whether or
+// not the wait timed out) into waits, and step intervals, as measured by
+// clock, into steps. Either histogram may be nil, in which case those
+// values are not recorded; if clock is nil, the SystemClock is used.
+func NewHistogramObserver(waits, steps *Histogram, clock Clock) *HistogramObserver {
+
+	return &HistogramObserver{
+		waits: waits,
+		steps: steps,
+		clock: clockOrSystem(clock),
+	}
+}
+
+func (o *HistogramObserver) Observe(e LatchEvent) {
+
+	switch e.Kind {
+	case LatchWaited, LatchTimedOut:
+
+		if o.waits != nil {
+
+			o.waits.ObserveDuration(e.Duration)
+		}
+	case LatchStepped, LatchFlipped:
+
+		if o.steps == nil || e.Latch == nil {
+
+			return
+		}
+
+		now := o.clock.Now()
+
+		var previous any
+		var found bool
+
+		if e.Kind == LatchFlipped {
+
+			previous, found = o.lastSteps.LoadAndDelete(e.Latch)
+		} else {
+
+			previous, found = o.lastSteps.Swap(e.Latch, now)
+		}
+
+		if found {
+
+			o.steps.ObserveDuration(now.Sub(previous.(time.Time)))
+		}
+	}
+}
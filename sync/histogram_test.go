@@ -0,0 +1,278 @@
+package sync_test
+
+import (
+	. "github.com/synesissoftware/syngo/sync"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/synesissoftware/syngo/sync/synctesting"
+
+	"context"
+	"math"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_Histogram_buckets(t *testing.T) {
+
+	t.Run("ExponentialBuckets()", func(t *testing.T) {
+
+		require.Equal(t, []float64{1, 2, 4, 8}, ExponentialBuckets(1, 2, 4))
+	})
+
+	t.Run("LinearBuckets()", func(t *testing.T) {
+
+		require.Equal(t, []float64{-1, 1, 3}, LinearBuckets(-1, 2, 3))
+	})
+
+	t.Run("invalid parameters panic", func(t *testing.T) {
+
+		require.Panics(t, func() { _ = ExponentialBuckets(0, 2, 4) })
+		require.Panics(t, func() { _ = ExponentialBuckets(1, 1, 4) })
+		require.Panics(t, func() { _ = ExponentialBuckets(1, 2, 0) })
+		require.Panics(t, func() { _ = LinearBuckets(0, 0, 4) })
+		require.Panics(t, func() { _ = LinearBuckets(0, 1, 0) })
+	})
+
+	t.Run("NewHistogram() with invalid bounds panics", func(t *testing.T) {
+
+		for _, bounds := range [][]float64{
+			nil,
+			{1, 1},
+			{2, 1},
+			{1, math.Inf(1)},
+			{math.NaN()},
+		} {
+
+			require.Panics(t, func() { _ = NewHistogram(bounds) }, "bounds: %v", bounds)
+		}
+	})
+}
+
+func Test_Histogram(t *testing.T) {
+
+	t.Run("Observe() counts values into buckets by upper bound", func(t *testing.T) {
+
+		h := NewHistogram([]float64{1, 2, 4})
+
+		h.Observe(0.5)
+		h.Observe(1)
+		h.Observe(1.5)
+		h.Observe(4)
+		h.Observe(100)
+		h.Observe(math.NaN())
+
+		s := h.Snapshot()
+
+		require.Equal(t, []float64{1, 2, 4}, s.Bounds)
+		require.Equal(t, []int64{2, 1, 1, 1}, s.Counts)
+		require.Equal(t, int64(5), s.Count)
+		require.Equal(t, 107.0, s.Sum)
+		require.Equal(t, 107.0/5, s.Mean())
+	})
+
+	t.Run("ObserveDuration() records seconds", func(t *testing.T) {
+
+		h := NewHistogram([]float64{0.001, 0.01, 0.1})
+
+		h.ObserveDuration(5 * time.Millisecond)
+
+		s := h.Snapshot()
+
+		require.Equal(t, []int64{0, 1, 0, 0}, s.Counts)
+		require.InDelta(t, 0.005, s.Sum, 1e-12)
+	})
+
+	t.Run("SnapshotAndReset() and Reset()", func(t *testing.T) {
+
+		h := NewHistogram([]float64{1})
+
+		h.Observe(1)
+		h.Observe(2)
+
+		s := h.SnapshotAndReset()
+
+		require.Equal(t, int64(2), s.Count)
+		require.Equal(t, 3.0, s.Sum)
+
+		s = h.Snapshot()
+
+		require.Equal(t, int64(0), s.Count)
+		require.Equal(t, 0.0, s.Sum)
+		require.True(t, math.IsNaN(s.Mean()))
+
+		h.Observe(1)
+		h.Reset()
+
+		require.Equal(t, []int64{0, 0}, h.Snapshot().Counts)
+	})
+
+	t.Run("concurrent Observe() loses no values", func(t *testing.T) {
+
+		const numGoroutines = 8
+		const numObservations = 1_000
+
+		h := NewHistogram(LinearBuckets(0, 10, 10))
+
+		var wg sync.WaitGroup
+
+		for range numGoroutines {
+
+			wg.Go(func() {
+
+				for i := range numObservations {
+
+					h.Observe(float64(i % 100))
+				}
+			})
+		}
+
+		wg.Wait()
+
+		s := h.Snapshot()
+
+		require.Equal(t, int64(numGoroutines*numObservations), s.Count)
+		require.Equal(t, float64(numGoroutines*numObservations/100*4950), s.Sum)
+	})
+}
+
+func Test_HistogramSnapshot(t *testing.T) {
+
+	t.Run("Merge()", func(t *testing.T) {
+
+		h1 := NewHistogram([]float64{1, 2})
+		h2 := NewHistogram([]float64{1, 2})
+
+		h1.Observe(1)
+		h2.Observe(2)
+		h2.Observe(3)
+
+		merged, err := h1.Snapshot().Merge(h2.Snapshot())
+
+		require.Nil(t, err)
+		require.Equal(t, []int64{1, 1, 1}, merged.Counts)
+		require.Equal(t, int64(3), merged.Count)
+		require.Equal(t, 6.0, merged.Sum)
+	})
+
+	t.Run("Merge() with different bounds fails", func(t *testing.T) {
+
+		h1 := NewHistogram([]float64{1, 2})
+		h2 := NewHistogram([]float64{1, 3})
+
+		_, err := h1.Snapshot().Merge(h2.Snapshot())
+
+		require.ErrorIs(t, err, ErrHistogramBoundsMismatch)
+	})
+
+	t.Run("Quantile() interpolates within buckets", func(t *testing.T) {
+
+		h := NewHistogram([]float64{10, 20, 40})
+
+		for range 50 {
+
+			h.Observe(5)
+		}
+
+		for range 50 {
+
+			h.Observe(30)
+		}
+
+		s := h.Snapshot()
+
+		assert.Equal(t, 0.0, s.Quantile(0))
+		assert.Equal(t, 5.0, s.Quantile(0.25))
+		assert.Equal(t, 10.0, s.Quantile(0.5))
+		assert.Equal(t, 30.0, s.Quantile(0.75))
+		assert.Equal(t, 40.0, s.Quantile(1))
+	})
+
+	t.Run("Quantile() in the overflow bucket is the last bound", func(t *testing.T) {
+
+		h := NewHistogram([]float64{1})
+
+		h.Observe(100)
+
+		require.Equal(t, 1.0, h.Snapshot().Quantile(0.99))
+	})
+
+	t.Run("Quantile() of an empty snapshot is NaN", func(t *testing.T) {
+
+		require.True(t, math.IsNaN(NewHistogram([]float64{1}).Snapshot().Quantile(0.5)))
+	})
+
+	t.Run("Quantile() out of range panics", func(t *testing.T) {
+
+		s := NewHistogram([]float64{1}).Snapshot()
+
+		require.Panics(t, func() { _ = s.Quantile(-0.1) })
+		require.Panics(t, func() { _ = s.Quantile(1.1) })
+		require.Panics(t, func() { _ = s.Quantile(math.NaN()) })
+	})
+}
+
+func Test_HistogramObserver(t *testing.T) {
+
+	t.Run("records step intervals until the latch flips", func(t *testing.T) {
+
+		clock := &synctesting.FakeClock{}
+		steps := NewHistogram([]float64{1, 2, 4})
+
+		latch := NewDownLatch(3, 0)
+
+		latch.SetObserver(NewHistogramObserver(nil, steps, clock))
+
+		latch.Step()
+		clock.Advance(time.Second)
+		latch.Step()
+		clock.Advance(3 * time.Second)
+		latch.Step()
+		clock.Advance(time.Second)
+		latch.Step()
+
+		s := steps.Snapshot()
+
+		require.Equal(t, []int64{1, 0, 1, 0}, s.Counts)
+		require.Equal(t, 4.0, s.Sum)
+	})
+
+	t.Run("records wait durations", func(t *testing.T) {
+
+		waits := NewHistogram(ExponentialBuckets(0.001, 10, 4))
+
+		latch := NewBoolLatch()
+
+		latch.SetObserver(NewHistogramObserver(waits, nil, nil))
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		cancel()
+
+		require.ErrorIs(t, latch.WaitContext(ctx), context.Canceled)
+
+		latch.Set()
+		latch.Wait()
+
+		require.Equal(t, int64(2), waits.Snapshot().Count)
+	})
+}
+
+func Benchmark_Histogram_Observe(b *testing.B) {
+
+	h := NewHistogram(ExponentialBuckets(0.0001, 2, 20))
+
+	b.RunParallel(func(pb *testing.PB) {
+
+		v := 0.0
+
+		for pb.Next() {
+
+			h.Observe(v)
+
+			v += 0.001
+		}
+	})
+}
@@ -4,7 +4,7 @@
 
 /*
  * Created: 14th March 2019
- * Updated: 2nd November 2025
+ * Updated: 19th October 2026
  */
 
 // Definition of a number of types that have one-way behaviour.
@@ -12,7 +12,9 @@
 package sync
 
 import (
+	"context"
 	"errors"
+	"iter"
 	"sync/atomic"
 	sync_atomic "sync/atomic"
 )
@@ -28,15 +30,29 @@ var (
 	errLatchDistanceExceedsMaximum                     = errors.New("latch distance exceeds maximum")
 )
 
+// Common interface satisfied by all latch types, allowing them to be
+// waited upon and combined (see AnyOf and AllOf) without regard to how
+// each one reaches its latched state.
+type Latch interface {
+	// Indicates whether the latch has latched.
+	IsLatched() bool
+	// Obtains a channel that is closed when the latch latches.
+	Done() <-chan struct{}
+}
+
 // A one-way switch that may be operated safely by multiple concurrent
 // goroutines.
 type BoolLatch struct {
+	_latchNotifier
+	_observed
 	value int64
 }
 
 // Creates a new BoolLatch.
 func NewBoolLatch() BoolLatch {
 
+	observeCreated("BoolLatch", 0)
+
 	return BoolLatch{}
 }
 
@@ -51,11 +67,18 @@ func (l *BoolLatch) Set() (flipped bool) {
 	if sync_atomic.CompareAndSwapInt64(&l.value, 0, 1) {
 
 		flipped = true
+
+		l._latchNotifier.fire()
 	} else {
 
 		flipped = false
 	}
 
+	if o := l._observed.load(); o != nil {
+
+		observeStep(o, "BoolLatch", l, flipped, true, 0)
+	}
+
 	return
 }
 
@@ -65,11 +88,69 @@ func (l *BoolLatch) Load() bool {
 	return 0 != sync_atomic.LoadInt64(&l.value)
 }
 
+// Indicates whether the latch has latched. Equivalent to Load().
+func (l *BoolLatch) IsLatched() bool {
+
+	return l.Load()
+}
+
+// Obtains a channel that is closed when the latch is set.
+func (l *BoolLatch) Done() <-chan struct{} {
+
+	return l._latchNotifier.done()
+}
+
+// Blocks the caller until the latch is set.
+func (l *BoolLatch) Wait() {
+
+	if o := l._observed.load(); o != nil {
+
+		observeWait(o, "BoolLatch", l, &l._latchNotifier, nil)
+
+		return
+	}
+
+	l._latchNotifier.wait()
+}
+
+// Blocks the caller until the latch is set or ctx is done.
+//
+// Returns:
+// nil if the latch was set; ctx.Err() otherwise
+func (l *BoolLatch) WaitContext(ctx context.Context) error {
+
+	if o := l._observed.load(); o != nil {
+
+		return observeWait(o, "BoolLatch", l, &l._latchNotifier, ctx)
+	}
+
+	return l._latchNotifier.waitContext(ctx)
+}
+
+// Installs o as the observer of the instance, in place of any global
+// observer. Passing nil reverts to the global observer.
+func (l *BoolLatch) SetObserver(o Observer) {
+
+	l._observed.set(o)
+}
+
+// Obtains the number of goroutines currently blocked in Wait() or
+// WaitContext().
+func (l *BoolLatch) NumWaiters() int {
+
+	return int(l._latchNotifier.numWaiters())
+}
+
 // Count-down-to-zero (and beyond, a bit) numeric latch
 type _baseLatch struct {
-	value int64
+	_latchNotifier
+	_observed
+	value   int64
+	waiters _counterWaiters // of Events() iterations
 }
 
+// Steps the latch. The step is reported to any observer by the DownLatch
+// or UpLatch in which l is embedded, which alone knows its type and count.
 func (l *_baseLatch) step() (flipped, isLatched bool, newCount int64) {
 
 	flipped = false
@@ -92,9 +173,91 @@ func (l *_baseLatch) step() (flipped, isLatched bool, newCount int64) {
 		atomic.SwapInt64(&l.value, latchedFloor)
 	}
 
+	if !isLatched || flipped {
+
+		l.waiters.notify(newCount)
+	}
+
+	if flipped {
+
+		l._latchNotifier.fire()
+	}
+
 	return
 }
 
+// Obtains the type name of owner, the DownLatch or UpLatch in which a
+// _baseLatch is embedded, and its count for the given remaining distance.
+func ownerCount(owner Latch, remaining int64) (typeName string, count int64) {
+
+	switch owner := owner.(type) {
+	case *DownLatch:
+
+		return "DownLatch", remaining + owner.addandR
+	case *UpLatch:
+
+		return "UpLatch", owner.subandL - remaining
+	default:
+
+		return "", remaining
+	}
+}
+
+// Obtains a sequence of the step events of owner, the DownLatch or UpLatch
+// in which l is embedded; see DownLatch.Events().
+func (l *_baseLatch) events(ctx context.Context, owner Latch) iter.Seq[LatchEvent] {
+
+	load := func() (remaining int64) {
+
+		_, remaining = l.load()
+
+		return
+	}
+
+	// steps made between this call and the iteration are reported
+	isLatched, since := l.load()
+
+	return func(yield func(LatchEvent) bool) {
+
+		if ctx.Err() != nil {
+
+			return
+		}
+
+		isLatched, last := isLatched, since
+
+		for !isLatched {
+
+			if _, err := l.waiters.waitFor(ctx, load, func(remaining int64) bool {
+
+				return remaining != last
+			}); err != nil {
+
+				return
+			}
+
+			isLatched, last = l.load()
+
+			e := LatchEvent{
+				Kind:  LatchStepped,
+				Latch: owner,
+			}
+
+			if isLatched {
+
+				e.Kind = LatchFlipped
+			}
+
+			e.Type, e.Count = ownerCount(owner, last)
+
+			if !yield(e) {
+
+				return
+			}
+		}
+	}
+}
+
 func (l *_baseLatch) load() (isLatched bool, count int64) {
 
 	count = atomic.LoadInt64(&l.value)
@@ -128,11 +291,14 @@ func NewDownLatch(initialValue, threshold int64) DownLatch {
 		panic(errDownLatchInitialValueMustBeGreaterThanThreshold)
 	}
 
-	if initialValue-threshold > MaxLatchDistance {
+	// the difference is exact as uint64, even where it overflows int64
+	if uint64(initialValue)-uint64(threshold) > uint64(MaxLatchDistance) {
 
 		panic(errLatchDistanceExceedsMaximum)
 	}
 
+	observeCreated("DownLatch", initialValue)
+
 	return DownLatch{
 		_baseLatch: _baseLatch{
 			value: initialValue - threshold,
@@ -147,6 +313,11 @@ func (l *DownLatch) Step() (flipped, isLatched bool, newCount int64) {
 
 	newCount += l.addandR
 
+	if o := l._observed.load(); o != nil {
+
+		observeStep(o, "DownLatch", l, flipped, isLatched, newCount)
+	}
+
 	return
 }
 
@@ -160,6 +331,78 @@ func (l *DownLatch) Load() (isLatched bool, count int64) {
 	return
 }
 
+// Indicates whether the latch has latched, without changing its state.
+func (l *DownLatch) IsLatched() (isLatched bool) {
+
+	isLatched, _ = l._baseLatch.load()
+
+	return
+}
+
+// Obtains a channel that is closed when the latch latches.
+func (l *DownLatch) Done() <-chan struct{} {
+
+	return l._latchNotifier.done()
+}
+
+// Blocks the caller until the latch latches.
+func (l *DownLatch) Wait() {
+
+	if o := l._observed.load(); o != nil {
+
+		observeWait(o, "DownLatch", l, &l._latchNotifier, nil)
+
+		return
+	}
+
+	l._latchNotifier.wait()
+}
+
+// Blocks the caller until the latch latches or ctx is done.
+//
+// Returns:
+// nil if the latch latched; ctx.Err() otherwise
+func (l *DownLatch) WaitContext(ctx context.Context) error {
+
+	if o := l._observed.load(); o != nil {
+
+		return observeWait(o, "DownLatch", l, &l._latchNotifier, ctx)
+	}
+
+	return l._latchNotifier.waitContext(ctx)
+}
+
+// Installs o as the observer of the instance, in place of any global
+// observer. Passing nil reverts to the global observer.
+func (l *DownLatch) SetObserver(o Observer) {
+
+	l._observed.set(o)
+}
+
+// Obtains a sequence of the events of the latch following this call -
+// LatchStepped for steps that do not latch it, and then LatchFlipped -
+// that ends when the latch flips, when ctx is done, or when the consumer
+// stops iterating, e.g.:
+//
+//	for e := range latch.Events(ctx) {
+//		...
+//	}
+//
+// Events are coalesced: if the latch is stepped more than once before the
+// consumer is ready, a single LatchStepped event, with the latest count, is
+// yielded. If the latch had already latched, the sequence is empty.
+func (l *DownLatch) Events(ctx context.Context) iter.Seq[LatchEvent] {
+
+	return l._baseLatch.events(ctx, l)
+}
+
+// Obtains the number of goroutines currently blocked in Wait() or
+// WaitContext().
+func (l *DownLatch) NumWaiters() int {
+
+	return int(l._latchNotifier.numWaiters())
+}
+
 // A unidirectional latch that counts up from an initial value to a higher
 // threshold that may be operated safely by multiple concurrent goroutines.
 type UpLatch struct {
@@ -179,11 +422,14 @@ func NewUpLatch(initialValue, threshold int64) UpLatch {
 		panic(errUpLatchInitialValueMustBeLessThanThreshold)
 	}
 
-	if threshold-initialValue > MaxLatchDistance {
+	// the difference is exact as uint64, even where it overflows int64
+	if uint64(threshold)-uint64(initialValue) > uint64(MaxLatchDistance) {
 
 		panic(errLatchDistanceExceedsMaximum)
 	}
 
+	observeCreated("UpLatch", initialValue)
+
 	return UpLatch{
 		_baseLatch: _baseLatch{
 			value: threshold - initialValue,
@@ -200,6 +446,11 @@ func (l *UpLatch) Step() (flipped, isLatched bool, newCount int64) {
 
 	newCount = l.subandL - count
 
+	if o := l._observed.load(); o != nil {
+
+		observeStep(o, "UpLatch", l, flipped, isLatched, newCount)
+	}
+
 	return
 }
 
@@ -214,3 +465,75 @@ func (l *UpLatch) Load() (isLatched bool, count int64) {
 
 	return
 }
+
+// Indicates whether the latch has latched, without changing its state.
+func (l *UpLatch) IsLatched() (isLatched bool) {
+
+	isLatched, _ = l._baseLatch.load()
+
+	return
+}
+
+// Obtains a channel that is closed when the latch latches.
+func (l *UpLatch) Done() <-chan struct{} {
+
+	return l._latchNotifier.done()
+}
+
+// Blocks the caller until the latch latches.
+func (l *UpLatch) Wait() {
+
+	if o := l._observed.load(); o != nil {
+
+		observeWait(o, "UpLatch", l, &l._latchNotifier, nil)
+
+		return
+	}
+
+	l._latchNotifier.wait()
+}
+
+// Blocks the caller until the latch latches or ctx is done.
+//
+// Returns:
+// nil if the latch latched; ctx.Err() otherwise
+func (l *UpLatch) WaitContext(ctx context.Context) error {
+
+	if o := l._observed.load(); o != nil {
+
+		return observeWait(o, "UpLatch", l, &l._latchNotifier, ctx)
+	}
+
+	return l._latchNotifier.waitContext(ctx)
+}
+
+// Installs o as the observer of the instance, in place of any global
+// observer. Passing nil reverts to the global observer.
+func (l *UpLatch) SetObserver(o Observer) {
+
+	l._observed.set(o)
+}
+
+// Obtains a sequence of the events of the latch following this call -
+// LatchStepped for steps that do not latch it, and then LatchFlipped -
+// that ends when the latch flips, when ctx is done, or when the consumer
+// stops iterating, e.g.:
+//
+//	for e := range latch.Events(ctx) {
+//		...
+//	}
+//
+// Events are coalesced: if the latch is stepped more than once before the
+// consumer is ready, a single LatchStepped event, with the latest count, is
+// yielded. If the latch had already latched, the sequence is empty.
+func (l *UpLatch) Events(ctx context.Context) iter.Seq[LatchEvent] {
+
+	return l._baseLatch.events(ctx, l)
+}
+
+// Obtains the number of goroutines currently blocked in Wait() or
+// WaitContext().
+func (l *UpLatch) NumWaiters() int {
+
+	return int(l._latchNotifier.numWaiters())
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"context"
+	"math/big"
 	"runtime"
 	"sync"
 	"sync/atomic"
@@ -143,6 +145,40 @@ func Test_DownLatch(t *testing.T) {
 		assert.True(t, true)
 	})
 
+	t.Run("NewDownLatch() panics unless the distance is in range, at every boundary", func(t *testing.T) {
+
+		maxDistance := big.NewInt(MaxLatchDistance)
+
+		for _, initial := range int64Boundaries {
+
+			for _, threshold := range int64Boundaries {
+
+				distance := int64Distance(initial, threshold)
+
+				if distance.Sign() <= 0 || distance.Cmp(maxDistance) > 0 {
+
+					require.Panics(t, func() {
+
+						_ = NewDownLatch(initial, threshold)
+					}, "[%d, %d)", initial, threshold)
+
+					continue
+				}
+
+				latch := NewDownLatch(initial, threshold)
+
+				isLatched, count := latch.Load()
+
+				require.False(t, isLatched)
+				require.Equal(t, initial, count)
+
+				flipped, _, _ := latch.Step()
+
+				require.Equal(t, distance.IsInt64() && distance.Int64() == 1, flipped, "[%d, %d)", initial, threshold)
+			}
+		}
+	})
+
 	t.Run("Load() without Set()", func(t *testing.T) {
 
 		latch := NewDownLatch(1, 0)
@@ -354,6 +390,40 @@ func Test_UpLatch(t *testing.T) {
 		assert.True(t, true)
 	})
 
+	t.Run("NewUpLatch() panics unless the distance is in range, at every boundary", func(t *testing.T) {
+
+		maxDistance := big.NewInt(MaxLatchDistance)
+
+		for _, initial := range int64Boundaries {
+
+			for _, threshold := range int64Boundaries {
+
+				distance := int64Distance(threshold, initial)
+
+				if distance.Sign() <= 0 || distance.Cmp(maxDistance) > 0 {
+
+					require.Panics(t, func() {
+
+						_ = NewUpLatch(initial, threshold)
+					}, "[%d, %d)", initial, threshold)
+
+					continue
+				}
+
+				latch := NewUpLatch(initial, threshold)
+
+				isLatched, count := latch.Load()
+
+				require.False(t, isLatched)
+				require.Equal(t, initial, count)
+
+				flipped, _, _ := latch.Step()
+
+				require.Equal(t, distance.IsInt64() && distance.Int64() == 1, flipped, "[%d, %d)", initial, threshold)
+			}
+		}
+	})
+
 	t.Run("Load() without Set(), for a latch of range [1, 3)", func(t *testing.T) {
 
 		latch := NewUpLatch(1, 3)
@@ -469,3 +539,271 @@ func Test_UpLatch(t *testing.T) {
 		}
 	})
 }
+
+func Test_Latch_waiting(t *testing.T) {
+
+	t.Run("BoolLatch Wait() returns once Set()", func(t *testing.T) {
+
+		latch := NewBoolLatch()
+
+		var wg sync.WaitGroup
+
+		for i := 0; i != 10; i++ {
+
+			wg.Go(func() {
+
+				latch.Wait()
+
+				assert.True(t, latch.IsLatched())
+			})
+		}
+
+		latch.Set()
+
+		wg.Wait()
+	})
+
+	t.Run("BoolLatch Done() requested after Set() is closed", func(t *testing.T) {
+
+		latch := NewBoolLatch()
+
+		latch.Set()
+
+		select {
+		case <-latch.Done():
+		default:
+
+			require.Fail(t, "Done() channel not closed")
+		}
+	})
+
+	t.Run("BoolLatch Done() requested concurrently with Set() is closed", func(t *testing.T) {
+
+		for range 1_000 {
+
+			latch := NewBoolLatch()
+
+			var wg sync.WaitGroup
+
+			wg.Go(func() {
+
+				latch.Set()
+			})
+
+			done := latch.Done()
+
+			wg.Wait()
+
+			select {
+			case <-done:
+			default:
+
+				require.Fail(t, "Done() channel not closed")
+			}
+		}
+	})
+
+	t.Run("BoolLatch WaitContext() honours cancellation", func(t *testing.T) {
+
+		latch := NewBoolLatch()
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		cancel()
+
+		require.ErrorIs(t, latch.WaitContext(ctx), context.Canceled)
+		require.False(t, latch.IsLatched())
+	})
+
+	t.Run("DownLatch Done() is closed only on flipping", func(t *testing.T) {
+
+		latch := NewDownLatch(3, 1)
+
+		done := latch.Done()
+
+		flipped, _, _ := latch.Step()
+
+		require.False(t, flipped)
+
+		select {
+		case <-done:
+
+			require.Fail(t, "Done() channel closed before flipping")
+		default:
+		}
+
+		require.False(t, latch.IsLatched())
+
+		flipped, _, _ = latch.Step()
+
+		require.True(t, flipped)
+
+		<-done
+
+		require.True(t, latch.IsLatched())
+		require.Nil(t, latch.WaitContext(context.Background()))
+	})
+
+	t.Run("UpLatch Wait() returns once flipped from many goroutines", func(t *testing.T) {
+
+		const numGoroutines = 10
+		const numSteps = 1_000
+
+		latch := NewUpLatch(0, numGoroutines*numSteps)
+
+		var wg sync.WaitGroup
+
+		for i := 0; i != numGoroutines; i++ {
+
+			wg.Go(func() {
+
+				for j := 0; j != numSteps; j++ {
+
+					_, _, _ = latch.Step()
+				}
+			})
+		}
+
+		latch.Wait()
+
+		wg.Wait()
+
+		isLatched, count := latch.Load()
+
+		require.True(t, isLatched)
+		require.Equal(t, int64(numGoroutines*numSteps), count)
+	})
+}
+
+func Test_Latch_events(t *testing.T) {
+
+	t.Run("DownLatch Events() yields steps and then the flip", func(t *testing.T) {
+
+		latch := NewDownLatch(3, 0)
+
+		events := latch.Events(context.Background())
+
+		acks := make(chan struct{})
+
+		var wg sync.WaitGroup
+
+		wg.Go(func() {
+
+			for i := 0; i != 3; i++ {
+
+				latch.Step()
+
+				<-acks
+			}
+		})
+
+		var received []LatchEvent
+
+		for e := range events {
+
+			received = append(received, e)
+
+			acks <- struct{}{}
+		}
+
+		wg.Wait()
+
+		require.Len(t, received, 3)
+
+		require.Equal(t, LatchStepped, received[0].Kind)
+		require.Equal(t, int64(2), received[0].Count)
+		require.Equal(t, "DownLatch", received[0].Type)
+		require.Same(t, &latch, received[0].Latch)
+
+		require.Equal(t, LatchStepped, received[1].Kind)
+		require.Equal(t, int64(1), received[1].Count)
+
+		require.Equal(t, LatchFlipped, received[2].Kind)
+		require.Equal(t, int64(0), received[2].Count)
+	})
+
+	t.Run("UpLatch Events() coalesces steps", func(t *testing.T) {
+
+		latch := NewUpLatch(0, 100)
+
+		var wg sync.WaitGroup
+
+		wg.Go(func() {
+
+			for i := 0; i != 100; i++ {
+
+				latch.Step()
+			}
+		})
+
+		var last LatchEvent
+
+		numEvents := 0
+
+		for e := range latch.Events(context.Background()) {
+
+			require.Greater(t, e.Count, last.Count)
+
+			last = e
+
+			numEvents++
+		}
+
+		wg.Wait()
+
+		require.LessOrEqual(t, numEvents, 100)
+		require.Equal(t, LatchFlipped, last.Kind)
+		require.Equal(t, int64(100), last.Count)
+	})
+
+	t.Run("Events() of a latched latch is empty", func(t *testing.T) {
+
+		latch := NewUpLatch(0, 1)
+
+		latch.Step()
+
+		for range latch.Events(context.Background()) {
+
+			require.Fail(t, "unexpected event")
+		}
+	})
+
+	t.Run("Events() ends when ctx is done", func(t *testing.T) {
+
+		latch := NewDownLatch(10, 0)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		events := latch.Events(ctx)
+
+		var received []LatchEvent
+		var wg sync.WaitGroup
+
+		wg.Go(func() {
+
+			for e := range events {
+
+				received = append(received, e)
+			}
+		})
+
+		_, isLatched, _ := latch.Step()
+
+		require.False(t, isLatched)
+
+		cancel()
+
+		wg.Wait()
+
+		// the step is reported unless the iteration began after
+		// cancellation
+		require.LessOrEqual(t, len(received), 1)
+
+		for _, e := range received {
+
+			require.Equal(t, LatchStepped, e.Kind)
+			require.Equal(t, int64(9), e.Count)
+		}
+	})
+}
@@ -0,0 +1,334 @@
+// Copyright 2019-2026 Matthew Wilson and Synesis Information Systems. All
+// rights reserved. Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+ * Created: 19th October 2026
+ * Updated:
+ */
+
+// Definition of the once-only notification mechanism shared by the latch
+// types.
+
+package sync
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// Records whether a latch has fired, and notifies interested parties -
+// blocked waiters, channel receivers, and subscribed callbacks - exactly
+// once when it does.
+//
+// The zero value is ready for use. The state proper is allocated only when
+// it is first needed - by done(), wait(), waitContext(), or subscribe() -
+// so a latch that is only stepped (or set) carries no more than a pointer
+// and a word of flags, and its flip costs a single atomic operation.
+// Neither is touched on the (non-flipping) step paths of the latch types.
+//
+// The pointer is published through the flags, rather than being atomic in
+// its own right, since the atomic pointer operations would cause any latch
+// that is stepped to escape to the heap.
+type _latchNotifier struct {
+	state *_notifierState // written once, before notifierInstalled is set
+	flags int32
+}
+
+const (
+	notifierFired      int32 = 1 << iota
+	notifierInstalling       // the state is being allocated
+	notifierInstalled        // the state may be read
+)
+
+// The state of a _latchNotifier.
+type _notifierState struct {
+	mx      sync.Mutex
+	fired   bool
+	ch      chan struct{}
+	nextId  int64
+	onFire  map[int64]func()
+	waiting _waiterCount
+}
+
+// Obtains the state of the notifier, allocating it if necessary.
+//
+// A state installed after the notifier has fired is fired here, since
+// fire() and the installation each set their flag and see whether the
+// other's has been set by a single atomic operation on the same word.
+func (n *_latchNotifier) get() *_notifierState {
+
+	for {
+
+		flags := atomic.LoadInt32(&n.flags)
+
+		switch {
+		case flags&notifierInstalled != 0:
+
+			return n.state
+		case flags&notifierInstalling != 0:
+
+			// another goroutine is between the two steps below
+			runtime.Gosched()
+		case atomic.CompareAndSwapInt32(&n.flags, flags, flags|notifierInstalling):
+
+			s := new(_notifierState)
+
+			n.state = s
+
+			if atomic.OrInt32(&n.flags, notifierInstalled)&notifierFired != 0 {
+
+				s.fire()
+			}
+
+			return s
+		}
+	}
+}
+
+// Marks the notifier as fired, notifying all interested parties. Only the
+// first call has any effect.
+func (n *_latchNotifier) fire() (fired bool) {
+
+	flags := atomic.OrInt32(&n.flags, notifierFired)
+
+	if flags&notifierFired != 0 {
+
+		return
+	}
+
+	if flags&notifierInstalled != 0 {
+
+		n.state.fire()
+	}
+
+	fired = true
+
+	return
+}
+
+func (n *_latchNotifier) isFired() bool {
+
+	return atomic.LoadInt32(&n.flags)&notifierFired != 0
+}
+
+func (n *_latchNotifier) done() <-chan struct{} {
+
+	return n.get().done()
+}
+
+func (n *_latchNotifier) wait() {
+
+	n.get().wait()
+}
+
+func (n *_latchNotifier) waitContext(ctx context.Context) error {
+
+	return n.get().waitContext(ctx)
+}
+
+func (n *_latchNotifier) numWaiters() int64 {
+
+	if atomic.LoadInt32(&n.flags)&notifierInstalled != 0 {
+
+		return n.state.numWaiters()
+	}
+
+	return 0
+}
+
+// Registers fn to be called (once) when the notifier fires. If it has
+// already fired, fn is called immediately, on the calling goroutine.
+//
+// Returns:
+// a function that removes the registration; it is safe to call more than
+// once, and after the notifier has fired
+func (n *_latchNotifier) subscribe(fn func()) (unsubscribe func()) {
+
+	return n.get().subscribe(fn)
+}
+
+// Marks the state as fired, closes the done channel (if one has been
+// requested), and invokes all subscribed callbacks. Only the first call has
+// any effect.
+func (s *_notifierState) fire() (fired bool) {
+
+	s.mx.Lock()
+
+	if s.fired {
+
+		s.mx.Unlock()
+
+		return
+	}
+
+	s.fired = true
+
+	if s.ch != nil {
+
+		close(s.ch)
+	}
+
+	callbacks := s.onFire
+
+	s.onFire = nil
+
+	s.mx.Unlock()
+
+	for _, fn := range callbacks {
+
+		fn()
+	}
+
+	fired = true
+
+	return
+}
+
+func (s *_notifierState) isFired() (fired bool) {
+
+	s.mx.Lock()
+	fired = s.fired
+	s.mx.Unlock()
+
+	return
+}
+
+func (s *_notifierState) done() <-chan struct{} {
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if s.ch == nil {
+
+		s.ch = make(chan struct{})
+
+		if s.fired {
+
+			close(s.ch)
+		}
+	}
+
+	return s.ch
+}
+
+func (s *_notifierState) wait() {
+
+	done := s.done()
+
+	select {
+	case <-done:
+
+		return
+	default:
+	}
+
+	token := s.waiting.begin()
+	defer s.waiting.end(token)
+
+	<-done
+}
+
+func (s *_notifierState) waitContext(ctx context.Context) error {
+
+	done := s.done()
+
+	select {
+	case <-done:
+
+		return nil
+	default:
+	}
+
+	token := s.waiting.begin()
+	defer s.waiting.end(token)
+
+	select {
+	case <-done:
+
+		return nil
+	case <-ctx.Done():
+
+		return ctx.Err()
+	}
+}
+
+func (s *_notifierState) numWaiters() int64 {
+
+	return s.waiting.load()
+}
+
+func (s *_notifierState) subscribe(fn func()) (unsubscribe func()) {
+
+	s.mx.Lock()
+
+	if s.fired {
+
+		s.mx.Unlock()
+
+		fn()
+
+		return func() {}
+	}
+
+	if s.onFire == nil {
+
+		s.onFire = make(map[int64]func())
+	}
+
+	id := s.nextId
+
+	s.nextId++
+
+	s.onFire[id] = fn
+
+	s.mx.Unlock()
+
+	return func() {
+
+		s.mx.Lock()
+		delete(s.onFire, id)
+		s.mx.Unlock()
+	}
+}
+
+// Implemented by all latch types defined in this package, allowing
+// composites to be notified without a goroutine per child.
+type _subscriber interface {
+	subscribe(fn func()) (unsubscribe func())
+}
+
+// Arranges for fn to be called when l latches. Latches from this package
+// are subscribed to directly; for any other implementation a goroutine is
+// used, which exits when the latch fires or when unsubscribe is called.
+func subscribeToLatch(l Latch, fn func()) (unsubscribe func()) {
+
+	if s, ok := l.(_subscriber); ok {
+
+		return s.subscribe(fn)
+	}
+
+	stop := make(chan struct{})
+
+	var once sync.Once
+
+	go func() {
+
+		select {
+		case <-l.Done():
+
+			fn()
+		case <-stop:
+		}
+	}()
+
+	return func() {
+
+		once.Do(func() {
+
+			close(stop)
+		})
+	}
+}
@@ -0,0 +1,293 @@
+// Copyright 2019-2026 Matthew Wilson and Synesis Information Systems. All
+// rights reserved. Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+ * Created: 19th October 2026
+ * Updated:
+ */
+
+// Definition of hooks by which the lifecycle events of latches may be
+// observed.
+
+package sync
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// The kind of a LatchEvent.
+type LatchEventKind int
+
+const (
+	// A latch has been created. Reported only to the global observer,
+	// since a latch cannot have an observer of its own until it exists.
+	LatchCreated LatchEventKind = iota
+	// A latch has been stepped without latching.
+	LatchStepped
+	// A latch has been stepped (or set) into the latched state.
+	LatchFlipped
+	// A latch has been stepped (or set) when already latched.
+	LatchSteppedAfterLatched
+	// A wait on a latch has completed because the latch latched.
+	LatchWaited
+	// A wait on a latch has completed because its context was done before
+	// the latch latched.
+	LatchTimedOut
+)
+
+func (k LatchEventKind) String() string {
+
+	switch k {
+	case LatchCreated:
+
+		return "created"
+	case LatchStepped:
+
+		return "stepped"
+	case LatchFlipped:
+
+		return "flipped"
+	case LatchSteppedAfterLatched:
+
+		return "stepped-after-latched"
+	case LatchWaited:
+
+		return "waited"
+	case LatchTimedOut:
+
+		return "timed-out"
+	default:
+
+		return "unknown"
+	}
+}
+
+// Describes an event in the lifecycle of a latch.
+type LatchEvent struct {
+	// The kind of event.
+	Kind LatchEventKind
+	// The name of the latch type, e.g. "DownLatch".
+	Type string
+	// The latch, or nil for LatchCreated (since the latch is returned by
+	// value from its constructor).
+	Latch Latch
+	// For a DownLatch, UpLatch, or SealableDownLatch, the count after the
+	// step (or, for LatchCreated, the initial value); likewise for a
+	// Uint64DownLatch or Uint64UpLatch, converted to int64 (so that a count
+	// greater than math.MaxInt64 is negative); for a BitLatch or
+	// WideBitLatch, the number of bits set; otherwise 0.
+	Count int64
+	// For LatchWaited and LatchTimedOut, the time spent in the wait;
+	// otherwise 0.
+	Duration time.Duration
+	// For LatchTimedOut, the error obtained from the context; otherwise
+	// nil.
+	Err error
+}
+
+// Receives latch lifecycle events.
+//
+// Observe() is called synchronously on the goroutine that causes the
+// event, so implementations must be safe for concurrent use and should
+// return promptly.
+type Observer interface {
+	Observe(e LatchEvent)
+}
+
+var (
+	globalObserver atomic.Pointer[Observer]
+)
+
+// Installs o as the observer of all latches that do not have an observer
+// of their own. Passing nil removes the global observer.
+//
+// Returns:
+// the previously installed global observer, or nil
+func SetObserver(o Observer) (previous Observer) {
+
+	var p *Observer
+
+	if o != nil {
+
+		p = &o
+	}
+
+	old := globalObserver.Swap(p)
+
+	if old != nil {
+
+		previous = *old
+	}
+
+	return
+}
+
+// Obtains the global observer, or nil if none is installed.
+func GlobalObserver() Observer {
+
+	if p := globalObserver.Load(); p != nil {
+
+		return *p
+	}
+
+	return nil
+}
+
+// Holds the per-instance observer of a latch.
+//
+// When neither the instance nor the package has an observer, the cost to
+// an operation is two atomic loads (which, on most platforms, are plain
+// loads), and no event is constructed. Since nothing is counted, an
+// instance that is discarded with an observer installed imposes no cost
+// on any other.
+type _observed struct {
+	observer atomic.Pointer[Observer]
+}
+
+func (o *_observed) set(observer Observer) {
+
+	var p *Observer
+
+	if observer != nil {
+
+		p = &observer
+	}
+
+	o.observer.Store(p)
+}
+
+// Obtains the instance's observer, or the global observer if the instance
+// has none, or nil.
+func (o *_observed) load() Observer {
+
+	if p := o.observer.Load(); p != nil {
+
+		return *p
+	}
+
+	if p := globalObserver.Load(); p != nil {
+
+		return *p
+	}
+
+	return nil
+}
+
+func observeCreated(typeName string, count int64) {
+
+	if p := globalObserver.Load(); p != nil {
+
+		(*p).Observe(LatchEvent{
+			Kind:  LatchCreated,
+			Type:  typeName,
+			Count: count,
+		})
+	}
+}
+
+func observeStep(o Observer, typeName string, latch Latch, flipped, isLatched bool, count int64) {
+
+	kind := LatchStepped
+
+	if flipped {
+
+		kind = LatchFlipped
+	} else if isLatched {
+
+		kind = LatchSteppedAfterLatched
+	}
+
+	o.Observe(LatchEvent{
+		Kind:  kind,
+		Type:  typeName,
+		Latch: latch,
+		Count: count,
+	})
+}
+
+// Waits on n, reporting the outcome to o, which must not be nil.
+func observeWait(o Observer, typeName string, latch Latch, n *_latchNotifier, ctx context.Context) (err error) {
+
+	start := time.Now()
+
+	if ctx == nil {
+
+		n.wait()
+	} else {
+
+		err = n.waitContext(ctx)
+	}
+
+	e := LatchEvent{
+		Kind:     LatchWaited,
+		Type:     typeName,
+		Latch:    latch,
+		Duration: time.Since(start),
+	}
+
+	if err != nil {
+
+		e.Kind = LatchTimedOut
+		e.Err = err
+	}
+
+	o.Observe(e)
+
+	return
+}
+
+// An Observer that logs latch events to a slog.Handler: flips are logged
+// at slog.LevelInfo, and all other events at slog.LevelDebug.
+type SlogObserver struct {
+	handler slog.Handler
+}
+
+// Creates a new SlogObserver that logs to h.
+func NewSlogObserver(h slog.Handler) *SlogObserver {
+
+	return &SlogObserver{
+		handler: h,
+	}
+}
+
+func (o *SlogObserver) Observe(e LatchEvent) {
+
+	level := slog.LevelDebug
+
+	if e.Kind == LatchFlipped {
+
+		level = slog.LevelInfo
+	}
+
+	ctx := context.Background()
+
+	if !o.handler.Enabled(ctx, level) {
+
+		return
+	}
+
+	r := slog.NewRecord(time.Now(), level, "latch "+e.Kind.String(), 0)
+
+	r.AddAttrs(slog.String("type", e.Type))
+
+	switch e.Kind {
+	case LatchWaited:
+
+		r.AddAttrs(slog.Duration("duration", e.Duration))
+	case LatchTimedOut:
+
+		r.AddAttrs(slog.Duration("duration", e.Duration), slog.Any("error", e.Err))
+	default:
+
+		if e.Type != "BoolLatch" {
+
+			r.AddAttrs(slog.Int64("count", e.Count))
+		}
+	}
+
+	_ = o.handler.Handle(ctx, r)
+}
@@ -0,0 +1,226 @@
+package sync_test
+
+import (
+	. "github.com/synesissoftware/syngo/sync"
+
+	"github.com/stretchr/testify/require"
+
+	"bytes"
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+)
+
+type recordingObserver struct {
+	mx     sync.Mutex
+	events []LatchEvent
+}
+
+func (o *recordingObserver) Observe(e LatchEvent) {
+
+	o.mx.Lock()
+	o.events = append(o.events, e)
+	o.mx.Unlock()
+}
+
+func (o *recordingObserver) kinds() (kinds []LatchEventKind) {
+
+	o.mx.Lock()
+	defer o.mx.Unlock()
+
+	for _, e := range o.events {
+
+		kinds = append(kinds, e.Kind)
+	}
+
+	return
+}
+
+func Test_Observer(t *testing.T) {
+
+	t.Run("per-instance observer of BoolLatch", func(t *testing.T) {
+
+		var observer recordingObserver
+
+		latch := NewBoolLatch()
+
+		latch.SetObserver(&observer)
+
+		latch.Set()
+		latch.Set()
+		latch.Wait()
+
+		require.Equal(t, []LatchEventKind{LatchFlipped, LatchSteppedAfterLatched, LatchWaited}, observer.kinds())
+		require.Equal(t, "BoolLatch", observer.events[0].Type)
+		require.Same(t, &latch, observer.events[0].Latch)
+	})
+
+	t.Run("per-instance observer of DownLatch", func(t *testing.T) {
+
+		var observer recordingObserver
+
+		latch := NewDownLatch(2, 0)
+
+		latch.SetObserver(&observer)
+
+		latch.Step()
+		latch.Step()
+		latch.Step()
+
+		require.Equal(t, []LatchEventKind{LatchStepped, LatchFlipped, LatchSteppedAfterLatched}, observer.kinds())
+		require.Equal(t, int64(1), observer.events[0].Count)
+		require.Equal(t, int64(0), observer.events[1].Count)
+	})
+
+	t.Run("per-instance observer of UpLatch", func(t *testing.T) {
+
+		var observer recordingObserver
+
+		latch := NewUpLatch(10, 11)
+
+		latch.SetObserver(&observer)
+
+		latch.Step()
+
+		require.Equal(t, []LatchEventKind{LatchFlipped}, observer.kinds())
+		require.Equal(t, "UpLatch", observer.events[0].Type)
+		require.Equal(t, int64(11), observer.events[0].Count)
+	})
+
+	t.Run("WaitContext() reports timed out", func(t *testing.T) {
+
+		var observer recordingObserver
+
+		latch := NewDownLatch(1, 0)
+
+		latch.SetObserver(&observer)
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		cancel()
+
+		err := latch.WaitContext(ctx)
+
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, []LatchEventKind{LatchTimedOut}, observer.kinds())
+		require.ErrorIs(t, observer.events[0].Err, context.Canceled)
+	})
+
+	t.Run("global observer, overridden per instance", func(t *testing.T) {
+
+		var global, local recordingObserver
+
+		previous := SetObserver(&global)
+
+		defer SetObserver(previous)
+
+		require.Same(t, &global, GlobalObserver())
+
+		latch1 := NewDownLatch(1, 0)
+		latch2 := NewDownLatch(1, 0)
+
+		latch2.SetObserver(&local)
+
+		latch1.Step()
+		latch2.Step()
+
+		require.Equal(t, []LatchEventKind{LatchCreated, LatchCreated, LatchFlipped}, global.kinds())
+		require.Equal(t, "DownLatch", global.events[0].Type)
+		require.Nil(t, global.events[0].Latch)
+		require.Equal(t, int64(1), global.events[0].Count)
+
+		require.Equal(t, []LatchEventKind{LatchFlipped}, local.kinds())
+
+		latch2.SetObserver(nil)
+
+		latch2.Step()
+
+		require.Equal(t, []LatchEventKind{LatchCreated, LatchCreated, LatchFlipped, LatchSteppedAfterLatched}, global.kinds())
+	})
+
+	t.Run("no observer", func(t *testing.T) {
+
+		require.Nil(t, GlobalObserver())
+
+		latch := NewBoolLatch()
+
+		require.True(t, latch.Set())
+	})
+
+	t.Run("LatchEventKind.String()", func(t *testing.T) {
+
+		require.Equal(t, "flipped", LatchFlipped.String())
+		require.Equal(t, "stepped-after-latched", LatchSteppedAfterLatched.String())
+		require.Equal(t, "unknown", LatchEventKind(-1).String())
+	})
+}
+
+func Test_SlogObserver(t *testing.T) {
+
+	t.Run("logs flips at Info and post-latch steps at Debug", func(t *testing.T) {
+
+		var buf bytes.Buffer
+
+		h := slog.NewTextHandler(&buf, &slog.HandlerOptions{
+			Level: slog.LevelDebug,
+			ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+
+				if a.Key == slog.TimeKey {
+
+					return slog.Attr{}
+				}
+
+				return a
+			},
+		})
+
+		latch := NewDownLatch(1, 0)
+
+		latch.SetObserver(NewSlogObserver(h))
+
+		latch.Step()
+		latch.Step()
+
+		require.Equal(t, "level=INFO msg=\"latch flipped\" type=DownLatch count=0\nlevel=DEBUG msg=\"latch stepped-after-latched\" type=DownLatch count=0\n", buf.String())
+	})
+
+	t.Run("events below the handler's level are not logged", func(t *testing.T) {
+
+		var buf bytes.Buffer
+
+		h := slog.NewTextHandler(&buf, nil)
+
+		latch := NewBoolLatch()
+
+		latch.SetObserver(NewSlogObserver(h))
+
+		latch.Set()
+		latch.Set()
+
+		require.Contains(t, buf.String(), "latch flipped")
+		require.NotContains(t, buf.String(), "stepped-after-latched")
+	})
+}
+
+func Benchmark_DownLatch_Step_without_observer(b *testing.B) {
+
+	latch := NewDownLatch(MaxLatchDistance, 0)
+
+	for b.Loop() {
+
+		latch.Step()
+	}
+}
+
+func Benchmark_DownLatch_Step_with_observer(b *testing.B) {
+
+	latch := NewDownLatch(MaxLatchDistance, 0)
+
+	latch.SetObserver(NewSlogObserver(slog.DiscardHandler))
+
+	for b.Loop() {
+
+		latch.Step()
+	}
+}
@@ -0,0 +1,343 @@
+// Copyright 2019-2026 Matthew Wilson and Synesis Information Systems. All
+// rights reserved. Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+ * Created: 19th October 2026
+ * Updated:
+ */
+
+// Definition of a startup orchestrator for components with dependencies.
+
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	// Returned by NewOrchestrator() when two components have the same
+	// name.
+	ErrOrchestratorDuplicateComponent = errors.New("orchestrator component is declared more than once")
+	// Returned by NewOrchestrator() when a component depends on a component
+	// that is not declared.
+	ErrOrchestratorUnknownDependency = errors.New("orchestrator component depends on an unknown component")
+	// Returned by NewOrchestrator() when the dependencies contain a cycle.
+	ErrOrchestratorCycle = errors.New("orchestrator components have a dependency cycle")
+	// The error of a component that was not started because one of its
+	// dependencies failed.
+	ErrOrchestratorDependencyFailed = errors.New("orchestrator component dependency failed")
+
+	errOrchestratorAlreadyStarted = errors.New("orchestrator may be started only once")
+)
+
+// Declares a component to be started by an Orchestrator.
+type Component struct {
+	// The name of the component, which must be unique within the
+	// orchestrator.
+	Name string
+	// The names of the components that must be ready before this component
+	// is started.
+	DependsOn []string
+	// Starts the component, returning once it is ready (nil) or has failed
+	// (non-nil). May be nil, in which case the component is ready as soon as
+	// its dependencies are.
+	Start func(ctx context.Context) error
+}
+
+type _orchestratedComponent struct {
+	Component
+	deps   []*_orchestratedComponent
+	ready  BoolLatch
+	failed BoolLatch
+	err    error // written before failed is set
+}
+
+// Starts a set of components, each as soon as all of its dependencies are
+// ready, with as many started in parallel as the dependencies permit.
+//
+// Each component has a readiness latch (see Ready()) that is set when its
+// Start function returns nil. When a component fails, none of the
+// components that depend upon it (directly or indirectly) are started, and
+// each of them fails with an error that satisfies
+// errors.Is(err, ErrOrchestratorDependencyFailed); components that do not
+// depend upon it are unaffected.
+type Orchestrator struct {
+	components []*_orchestratedComponent
+	byName     map[string]*_orchestratedComponent
+	started    int64
+}
+
+// Creates a new Orchestrator for the given components, verifying that all
+// dependencies are declared and that there are no cycles.
+//
+// Returns:
+// the orchestrator and nil; or nil and an error that satisfies one of
+// errors.Is(err, ErrOrchestratorDuplicateComponent),
+// errors.Is(err, ErrOrchestratorUnknownDependency), or
+// errors.Is(err, ErrOrchestratorCycle)
+func NewOrchestrator(components ...Component) (*Orchestrator, error) {
+
+	o := &Orchestrator{
+		components: make([]*_orchestratedComponent, 0, len(components)),
+		byName:     make(map[string]*_orchestratedComponent, len(components)),
+	}
+
+	for _, component := range components {
+
+		if _, exists := o.byName[component.Name]; exists {
+
+			return nil, fmt.Errorf("%w: %q", ErrOrchestratorDuplicateComponent, component.Name)
+		}
+
+		c := &_orchestratedComponent{
+			Component: component,
+		}
+
+		o.components = append(o.components, c)
+		o.byName[component.Name] = c
+	}
+
+	for _, c := range o.components {
+
+		for _, name := range c.DependsOn {
+
+			dep, exists := o.byName[name]
+
+			if !exists {
+
+				return nil, fmt.Errorf("%w: %q depends on %q", ErrOrchestratorUnknownDependency, c.Name, name)
+			}
+
+			c.deps = append(c.deps, dep)
+		}
+	}
+
+	if cycle := o.findCycle(); cycle != nil {
+
+		return nil, fmt.Errorf("%w: %s", ErrOrchestratorCycle, strings.Join(cycle, " -> "))
+	}
+
+	return o, nil
+}
+
+// Obtains the names of the components of a dependency cycle, with the
+// first repeated at the end, or nil if there is none.
+func (o *Orchestrator) findCycle() (cycle []string) {
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[*_orchestratedComponent]int, len(o.components))
+	path := make([]*_orchestratedComponent, 0, len(o.components))
+
+	var visit func(c *_orchestratedComponent) bool
+
+	visit = func(c *_orchestratedComponent) bool {
+
+		switch state[c] {
+		case visited:
+
+			return false
+		case visiting:
+
+			for i, p := range path {
+
+				if p == c {
+
+					for _, p := range path[i:] {
+
+						cycle = append(cycle, p.Name)
+					}
+
+					cycle = append(cycle, c.Name)
+
+					break
+				}
+			}
+
+			return true
+		}
+
+		state[c] = visiting
+		path = append(path, c)
+
+		for _, dep := range c.deps {
+
+			if visit(dep) {
+
+				return true
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[c] = visited
+
+		return false
+	}
+
+	for _, c := range o.components {
+
+		if visit(c) {
+
+			break
+		}
+	}
+
+	return
+}
+
+// Starts all components, in dependency order, and blocks the caller until
+// every component is ready or has failed. Components not yet started when
+// ctx is done fail with ctx.Err().
+//
+// Returns:
+// nil if all components are ready; otherwise the errors of all components
+// that failed, in declaration order, combined by errors.Join
+//
+// Preconditions:
+// - Start() has not previously been called;
+func (o *Orchestrator) Start(ctx context.Context) error {
+
+	if !atomic.CompareAndSwapInt64(&o.started, 0, 1) {
+
+		panic(errOrchestratorAlreadyStarted)
+	}
+
+	var wg sync.WaitGroup
+
+	for _, c := range o.components {
+
+		wg.Go(func() {
+
+			c.run(ctx)
+		})
+	}
+
+	wg.Wait()
+
+	var errs []error
+
+	for _, c := range o.components {
+
+		if c.err != nil {
+
+			errs = append(errs, c.err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *_orchestratedComponent) run(ctx context.Context) {
+
+	if len(c.deps) != 0 {
+
+		readies := make([]Latch, len(c.deps))
+		failures := make([]Latch, len(c.deps))
+
+		for i, dep := range c.deps {
+
+			readies[i] = &dep.ready
+			failures[i] = &dep.failed
+		}
+
+		allReady := AllOf(readies...)
+		defer allReady.Stop()
+
+		anyFailed := AnyOf(failures...)
+		defer anyFailed.Stop()
+
+		select {
+		case <-allReady.Done():
+		case <-anyFailed.Done():
+
+			c.fail(fmt.Errorf("%w: %q depends on %q", ErrOrchestratorDependencyFailed, c.Name, c.deps[anyFailed.Which()].Name))
+
+			return
+		case <-ctx.Done():
+
+			c.fail(ctx.Err())
+
+			return
+		}
+	}
+
+	if c.Start != nil {
+
+		if err := c.Start(ctx); err != nil {
+
+			c.fail(fmt.Errorf("orchestrator component %q: %w", c.Name, err))
+
+			return
+		}
+	}
+
+	c.ready.Set()
+}
+
+func (c *_orchestratedComponent) fail(err error) {
+
+	c.err = err
+
+	c.failed.Set()
+}
+
+// Obtains the readiness latch of the named component, which is set when
+// the component has started successfully, or nil if there is no such
+// component.
+func (o *Orchestrator) Ready(name string) *BoolLatch {
+
+	if c, exists := o.byName[name]; exists {
+
+		return &c.ready
+	}
+
+	return nil
+}
+
+// Obtains the error of the named component, which is nil unless it has
+// failed (or there is no such component).
+func (o *Orchestrator) Err(name string) error {
+
+	if c, exists := o.byName[name]; exists && c.failed.IsLatched() {
+
+		return c.err
+	}
+
+	return nil
+}
+
+// Renders the dependency graph in the DOT language of Graphviz, with an
+// edge from each component to each of its dependants.
+func (o *Orchestrator) DOT() string {
+
+	var sb strings.Builder
+
+	sb.WriteString("digraph orchestrator {\n")
+
+	for _, c := range o.components {
+
+		fmt.Fprintf(&sb, "\t%q;\n", c.Name)
+	}
+
+	for _, c := range o.components {
+
+		for _, dep := range c.deps {
+
+			fmt.Fprintf(&sb, "\t%q -> %q;\n", dep.Name, c.Name)
+		}
+	}
+
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
@@ -0,0 +1,236 @@
+package sync_test
+
+import (
+	. "github.com/synesissoftware/syngo/sync"
+
+	"github.com/stretchr/testify/require"
+
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+// Obtains a Start function that appends name to order when started, and
+// then returns err.
+func recordingStart(mx *sync.Mutex, order *[]string, name string, err error) func(ctx context.Context) error {
+
+	return func(ctx context.Context) error {
+
+		mx.Lock()
+		*order = append(*order, name)
+		mx.Unlock()
+
+		return err
+	}
+}
+
+func Test_Orchestrator(t *testing.T) {
+
+	t.Run("NewOrchestrator() rejects duplicate components", func(t *testing.T) {
+
+		o, err := NewOrchestrator(
+			Component{Name: "a"},
+			Component{Name: "a"},
+		)
+
+		require.Nil(t, o)
+		require.ErrorIs(t, err, ErrOrchestratorDuplicateComponent)
+	})
+
+	t.Run("NewOrchestrator() rejects unknown dependencies", func(t *testing.T) {
+
+		o, err := NewOrchestrator(
+			Component{Name: "a", DependsOn: []string{"b"}},
+		)
+
+		require.Nil(t, o)
+		require.ErrorIs(t, err, ErrOrchestratorUnknownDependency)
+	})
+
+	t.Run("NewOrchestrator() rejects cycles", func(t *testing.T) {
+
+		o, err := NewOrchestrator(
+			Component{Name: "a"},
+			Component{Name: "b", DependsOn: []string{"a", "d"}},
+			Component{Name: "c", DependsOn: []string{"b"}},
+			Component{Name: "d", DependsOn: []string{"c"}},
+		)
+
+		require.Nil(t, o)
+		require.ErrorIs(t, err, ErrOrchestratorCycle)
+		require.Contains(t, err.Error(), "b -> d -> c -> b")
+	})
+
+	t.Run("NewOrchestrator() rejects self-dependency", func(t *testing.T) {
+
+		_, err := NewOrchestrator(
+			Component{Name: "a", DependsOn: []string{"a"}},
+		)
+
+		require.ErrorIs(t, err, ErrOrchestratorCycle)
+	})
+
+	t.Run("Start() starts components in dependency order", func(t *testing.T) {
+
+		var mx sync.Mutex
+		var order []string
+
+		o, err := NewOrchestrator(
+			Component{Name: "server", DependsOn: []string{"cache", "db"}, Start: recordingStart(&mx, &order, "server", nil)},
+			Component{Name: "cache", DependsOn: []string{"config"}, Start: recordingStart(&mx, &order, "cache", nil)},
+			Component{Name: "db", DependsOn: []string{"config"}, Start: recordingStart(&mx, &order, "db", nil)},
+			Component{Name: "config", Start: recordingStart(&mx, &order, "config", nil)},
+		)
+
+		require.Nil(t, err)
+		require.False(t, o.Ready("config").IsLatched())
+
+		require.Nil(t, o.Start(context.Background()))
+
+		require.Len(t, order, 4)
+		require.Equal(t, "config", order[0])
+		require.ElementsMatch(t, []string{"cache", "db"}, order[1:3])
+		require.Equal(t, "server", order[3])
+
+		for _, name := range []string{"config", "cache", "db", "server"} {
+
+			require.True(t, o.Ready(name).IsLatched())
+			require.Nil(t, o.Err(name))
+		}
+
+		require.Nil(t, o.Ready("unknown"))
+	})
+
+	t.Run("independent components start in parallel", func(t *testing.T) {
+
+		barrier := NewDownLatch(2, 0)
+
+		start := func(ctx context.Context) error {
+
+			barrier.Step()
+
+			// neither can complete unless the other has also started
+			return barrier.WaitContext(ctx)
+		}
+
+		o, err := NewOrchestrator(
+			Component{Name: "a", Start: start},
+			Component{Name: "b", Start: start},
+		)
+
+		require.Nil(t, err)
+		require.Nil(t, o.Start(context.Background()))
+	})
+
+	t.Run("a failure aborts dependants only", func(t *testing.T) {
+
+		var mx sync.Mutex
+		var order []string
+
+		errDb := errors.New("db unavailable")
+
+		o, err := NewOrchestrator(
+			Component{Name: "config", Start: recordingStart(&mx, &order, "config", nil)},
+			Component{Name: "db", DependsOn: []string{"config"}, Start: recordingStart(&mx, &order, "db", errDb)},
+			Component{Name: "repo", DependsOn: []string{"db"}, Start: recordingStart(&mx, &order, "repo", nil)},
+			Component{Name: "server", DependsOn: []string{"repo", "config"}, Start: recordingStart(&mx, &order, "server", nil)},
+			Component{Name: "metrics", DependsOn: []string{"config"}, Start: recordingStart(&mx, &order, "metrics", nil)},
+		)
+
+		require.Nil(t, err)
+
+		err = o.Start(context.Background())
+
+		require.ErrorIs(t, err, errDb)
+		require.ErrorIs(t, err, ErrOrchestratorDependencyFailed)
+
+		require.ElementsMatch(t, []string{"config", "db", "metrics"}, order)
+
+		require.True(t, o.Ready("metrics").IsLatched())
+		require.False(t, o.Ready("repo").IsLatched())
+		require.False(t, o.Ready("server").IsLatched())
+
+		require.ErrorIs(t, o.Err("db"), errDb)
+		require.ErrorIs(t, o.Err("repo"), ErrOrchestratorDependencyFailed)
+		require.ErrorIs(t, o.Err("server"), ErrOrchestratorDependencyFailed)
+		require.Nil(t, o.Err("metrics"))
+	})
+
+	t.Run("components not started when ctx is done fail", func(t *testing.T) {
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		release := NewBoolLatch()
+
+		o, err := NewOrchestrator(
+			Component{Name: "a", Start: func(context.Context) error {
+
+				release.Wait()
+
+				return nil
+			}},
+			Component{Name: "b", DependsOn: []string{"a"}},
+		)
+
+		require.Nil(t, err)
+
+		done := make(chan error)
+
+		go func() {
+
+			done <- o.Start(ctx)
+		}()
+
+		cancel()
+
+		for !errors.Is(o.Err("b"), context.Canceled) {
+
+			runtime.Gosched()
+		}
+
+		release.Set()
+
+		err = <-done
+
+		require.ErrorIs(t, err, context.Canceled)
+		require.True(t, o.Ready("a").IsLatched())
+		require.False(t, o.Ready("b").IsLatched())
+	})
+
+	t.Run("Start() may be called only once", func(t *testing.T) {
+
+		o, _ := NewOrchestrator()
+
+		require.Nil(t, o.Start(context.Background()))
+
+		require.Panics(t, func() {
+
+			_ = o.Start(context.Background())
+		})
+	})
+
+	t.Run("DOT()", func(t *testing.T) {
+
+		o, err := NewOrchestrator(
+			Component{Name: "config"},
+			Component{Name: "db", DependsOn: []string{"config"}},
+			Component{Name: "server", DependsOn: []string{"db", "config"}},
+		)
+
+		require.Nil(t, err)
+
+		expected := `digraph orchestrator {
+	"config";
+	"db";
+	"server";
+	"config" -> "db";
+	"db" -> "server";
+	"config" -> "server";
+}
+`
+
+		require.Equal(t, expected, o.DOT())
+	})
+}
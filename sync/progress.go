@@ -0,0 +1,361 @@
+// Copyright 2019-2026 Matthew Wilson and Synesis Information Systems. All
+// rights reserved. Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+ * Created: 19th October 2026
+ * Updated:
+ */
+
+// Definition of a progress tracker for a fixed number of steps.
+
+package sync
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// The smoothing time constant used by NewProgress() when none is
+	// specified.
+	DefaultProgressSmoothing = 10 * time.Second
+)
+
+var (
+	errProgressTotalMustBePositive          = errors.New("progress total must be positive")
+	errProgressReportIntervalMustBePositive = errors.New("progress report interval must be positive")
+)
+
+// A snapshot of the state of a Progress.
+type ProgressReport struct {
+	// The total number of steps.
+	Total int64
+	// The number of steps completed.
+	Completed int64
+	// The number of steps remaining.
+	Remaining int64
+	// The percentage of steps completed, in the range [0, 100].
+	Percent float64
+	// The smoothed throughput, in steps per second.
+	Throughput float64
+	// The time since the Progress was created.
+	Elapsed time.Duration
+	// The estimated time until all steps are completed, which is 0 when
+	// they have been, and -1 when it cannot yet be estimated.
+	ETA time.Duration
+	// Indicates whether all steps are completed.
+	Done bool
+}
+
+// Tracks progress through a fixed number of steps, which may be taken by
+// multiple concurrent goroutines, providing a smoothed throughput and an
+// estimate of the time remaining.
+//
+// The remaining count is held in a DownLatch, so that a Progress may also
+// be waited upon, and used as a Latch. Throughput is an exponentially
+// weighted moving average, with a given time constant, of the rate of
+// completion between successive samples; samples are taken whenever the
+// state is queried, so that Step() does no more than step the latch and
+// record the time of the step.
+type Progress struct {
+	latch     DownLatch
+	total     int64
+	clock     Clock
+	origin    time.Time
+	smoothing time.Duration
+	lastStep  int64 // nanoseconds since origin of the most recent step
+	mx        sync.Mutex
+	sampledAt int64
+	sampled   int64
+	rate      float64
+	hasRate   bool
+}
+
+// Creates a new Progress of total steps, whose throughput is smoothed with
+// the time constant smoothing (or DefaultProgressSmoothing if it is not
+// positive), and whose times are obtained from clock; if clock is nil, the
+// SystemClock is used.
+//
+// Preconditions:
+// - total > 0;
+func NewProgress(total int64, smoothing time.Duration, clock Clock) *Progress {
+
+	if total <= 0 {
+
+		panic(errProgressTotalMustBePositive)
+	}
+
+	if smoothing <= 0 {
+
+		smoothing = DefaultProgressSmoothing
+	}
+
+	clock = clockOrSystem(clock)
+
+	return &Progress{
+		latch:     NewDownLatch(total, 0),
+		total:     total,
+		clock:     clock,
+		origin:    clock.Now(),
+		smoothing: smoothing,
+	}
+}
+
+func (p *Progress) now() int64 {
+
+	return int64(p.clock.Now().Sub(p.origin))
+}
+
+// Records the completion of a step.
+//
+// Returns:
+// flipped - true if this step completed the last step;
+// isLatched - true if all steps are completed;
+// remaining - the number of steps remaining;
+func (p *Progress) Step() (flipped, isLatched bool, remaining int64) {
+
+	// the time is recorded before stepping, so that it is in place by the
+	// time that Done() is closed, and is never replaced by an earlier one
+	// from a concurrent step
+	if now := p.now(); !p.latch.IsLatched() {
+
+		for {
+
+			lastStep := atomic.LoadInt64(&p.lastStep)
+
+			if now <= lastStep || atomic.CompareAndSwapInt64(&p.lastStep, lastStep, now) {
+
+				break
+			}
+		}
+	}
+
+	flipped, isLatched, remaining = p.latch.Step()
+
+	return
+}
+
+// Folds the steps completed since the previous sample into the smoothed
+// rate.
+//
+// Returns:
+// the smoothed rate, and whether there is one
+func (p *Progress) sample(now, completed int64) (rate float64, hasRate bool) {
+
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	if dt := now - p.sampledAt; dt > 0 {
+
+		instant := float64(completed-p.sampled) / time.Duration(dt).Seconds()
+
+		if p.hasRate {
+
+			alpha := 1 - math.Exp(-float64(dt)/float64(p.smoothing))
+
+			p.rate += alpha * (instant - p.rate)
+		} else if completed != 0 {
+
+			// the first completions determine the initial rate, so that the
+			// estimate does not have to climb from zero
+			p.rate = instant
+			p.hasRate = true
+		}
+
+		p.sampledAt = now
+		p.sampled = completed
+	}
+
+	return p.rate, p.hasRate
+}
+
+// Obtains a snapshot of the state of the instance.
+func (p *Progress) Report() (report ProgressReport) {
+
+	isLatched, remaining := p.latch.Load()
+
+	now := p.now()
+
+	if isLatched {
+
+		// elapsed time stops at the last step
+		now = atomic.LoadInt64(&p.lastStep)
+	}
+
+	completed := p.total - remaining
+
+	rate, hasRate := p.sample(now, completed)
+
+	report.Total = p.total
+	report.Completed = completed
+	report.Remaining = remaining
+	report.Percent = 100 * float64(completed) / float64(p.total)
+	report.Throughput = rate
+	report.Elapsed = time.Duration(now)
+	report.Done = isLatched
+
+	switch {
+	case isLatched:
+
+		report.ETA = 0
+	case !hasRate || rate <= 0:
+
+		report.ETA = -1
+	default:
+
+		eta := float64(remaining) / rate
+
+		if eta >= (math.MaxInt64 / float64(time.Second)) {
+
+			report.ETA = -1
+		} else {
+
+			report.ETA = time.Duration(eta * float64(time.Second))
+		}
+	}
+
+	return
+}
+
+// Obtains the percentage of steps completed, in the range [0, 100].
+func (p *Progress) Percent() float64 {
+
+	_, remaining := p.latch.Load()
+
+	return 100 * float64(p.total-remaining) / float64(p.total)
+}
+
+// Obtains the smoothed throughput, in steps per second.
+func (p *Progress) Throughput() float64 {
+
+	return p.Report().Throughput
+}
+
+// Obtains the estimated time until all steps are completed.
+//
+// Returns:
+// the estimate and true; or 0 and false if it cannot yet be estimated
+func (p *Progress) ETA() (eta time.Duration, ok bool) {
+
+	if eta = p.Report().ETA; eta < 0 {
+
+		return 0, false
+	}
+
+	return eta, true
+}
+
+// Indicates whether all steps are completed.
+func (p *Progress) IsLatched() bool {
+
+	return p.latch.IsLatched()
+}
+
+// Obtains a channel that is closed when all steps are completed.
+func (p *Progress) Done() <-chan struct{} {
+
+	return p.latch.Done()
+}
+
+// Blocks the caller until all steps are completed.
+func (p *Progress) Wait() {
+
+	p.latch.Wait()
+}
+
+// Blocks the caller until all steps are completed or ctx is done.
+//
+// Returns:
+// nil if all steps are completed; ctx.Err() otherwise
+func (p *Progress) WaitContext(ctx context.Context) error {
+
+	return p.latch.WaitContext(ctx)
+}
+
+// Obtains the number of goroutines currently blocked in Wait() or
+// WaitContext().
+func (p *Progress) NumWaiters() int {
+
+	return p.latch.NumWaiters()
+}
+
+// Calls fn with a report every interval, and once more when all steps are
+// completed, blocking the caller until then or until ctx is done.
+//
+// Returns:
+// nil if all steps are completed; ctx.Err() otherwise
+//
+// Preconditions:
+// - interval > 0;
+func (p *Progress) ReportEvery(ctx context.Context, interval time.Duration, fn func(report ProgressReport)) error {
+
+	if interval <= 0 {
+
+		panic(errProgressReportIntervalMustBePositive)
+	}
+
+	done := p.latch.Done()
+
+	for {
+
+		select {
+		case <-done:
+
+			fn(p.Report())
+
+			return nil
+		case <-ctx.Done():
+
+			return ctx.Err()
+		case <-p.clock.After(interval):
+
+			fn(p.Report())
+		}
+	}
+}
+
+// Obtains a channel on which a report is sent every interval, and once
+// more when all steps are completed, after which the channel is closed. The
+// channel is also closed when ctx is done. A report is discarded if the
+// previous one has not yet been received.
+//
+// Preconditions:
+// - interval > 0;
+func (p *Progress) Reports(ctx context.Context, interval time.Duration) <-chan ProgressReport {
+
+	if interval <= 0 {
+
+		panic(errProgressReportIntervalMustBePositive)
+	}
+
+	ch := make(chan ProgressReport, 1)
+
+	go func() {
+
+		defer close(ch)
+
+		_ = p.ReportEvery(ctx, interval, func(report ProgressReport) {
+
+			if report.Done {
+
+				// the final report replaces any not yet received
+				select {
+				case <-ch:
+				default:
+				}
+			}
+
+			select {
+			case ch <- report:
+			default:
+			}
+		})
+	}()
+
+	return ch
+}
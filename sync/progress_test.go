@@ -0,0 +1,304 @@
+package sync_test
+
+import (
+	. "github.com/synesissoftware/syngo/sync"
+
+	"github.com/synesissoftware/syngo/sync/synctesting"
+
+	"github.com/stretchr/testify/require"
+
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+type observerFunc func(e LatchEvent)
+
+func (f observerFunc) Observe(e LatchEvent) {
+
+	f(e)
+}
+
+func Test_Progress(t *testing.T) {
+
+	t.Run("NewProgress() with non-positive total panics", func(t *testing.T) {
+
+		require.Panics(t, func() {
+
+			_ = NewProgress(0, 0, nil)
+		})
+	})
+
+	t.Run("initial report", func(t *testing.T) {
+
+		clock := &synctesting.FakeClock{}
+
+		progress := NewProgress(10, time.Second, clock)
+
+		report := progress.Report()
+
+		require.Equal(t, int64(10), report.Total)
+		require.Equal(t, int64(0), report.Completed)
+		require.Equal(t, int64(10), report.Remaining)
+		require.Equal(t, 0.0, report.Percent)
+		require.Equal(t, 0.0, report.Throughput)
+		require.Equal(t, time.Duration(-1), report.ETA)
+		require.False(t, report.Done)
+
+		_, ok := progress.ETA()
+
+		require.False(t, ok)
+	})
+
+	t.Run("steady throughput", func(t *testing.T) {
+
+		clock := &synctesting.FakeClock{}
+
+		progress := NewProgress(100, time.Second, clock)
+
+		for i := 0; i != 40; i++ {
+
+			clock.Advance(100 * time.Millisecond)
+
+			progress.Step()
+
+			_ = progress.Report()
+		}
+
+		report := progress.Report()
+
+		require.Equal(t, int64(40), report.Completed)
+		require.Equal(t, int64(60), report.Remaining)
+		require.Equal(t, 40.0, report.Percent)
+		require.InDelta(t, 10.0, report.Throughput, 0.001)
+		require.InDelta(t, float64(6*time.Second), float64(report.ETA), float64(time.Millisecond))
+		require.Equal(t, 4*time.Second, report.Elapsed)
+		require.Equal(t, 40.0, progress.Percent())
+	})
+
+	t.Run("throughput follows a change of rate", func(t *testing.T) {
+
+		clock := &synctesting.FakeClock{}
+
+		progress := NewProgress(1000, time.Second, clock)
+
+		for i := 0; i != 20; i++ {
+
+			clock.Advance(100 * time.Millisecond)
+
+			progress.Step()
+
+			_ = progress.Report()
+		}
+
+		require.InDelta(t, 10.0, progress.Throughput(), 0.001)
+
+		for i := 0; i != 100; i++ {
+
+			clock.Advance(100 * time.Millisecond)
+
+			progress.Step()
+			progress.Step()
+
+			_ = progress.Report()
+		}
+
+		require.InDelta(t, 20.0, progress.Throughput(), 0.01)
+
+		// with no further steps, the throughput decays
+		clock.Advance(5 * time.Second)
+
+		require.Less(t, progress.Throughput(), 1.0)
+	})
+
+	t.Run("completion", func(t *testing.T) {
+
+		clock := &synctesting.FakeClock{}
+
+		progress := NewProgress(2, time.Second, clock)
+
+		clock.Advance(time.Second)
+
+		flipped, isLatched, remaining := progress.Step()
+
+		require.False(t, flipped)
+		require.False(t, isLatched)
+		require.Equal(t, int64(1), remaining)
+
+		clock.Advance(time.Second)
+
+		flipped, isLatched, remaining = progress.Step()
+
+		require.True(t, flipped)
+		require.True(t, isLatched)
+		require.Equal(t, int64(0), remaining)
+
+		clock.Advance(time.Hour)
+
+		report := progress.Report()
+
+		require.True(t, report.Done)
+		require.Equal(t, 100.0, report.Percent)
+		require.Equal(t, time.Duration(0), report.ETA)
+		require.Equal(t, 2*time.Second, report.Elapsed)
+
+		require.True(t, progress.IsLatched())
+		require.Nil(t, progress.WaitContext(context.Background()))
+	})
+
+	t.Run("the time of the last step is recorded by the time Done() is closed", func(t *testing.T) {
+
+		clock := &synctesting.FakeClock{}
+
+		progress := NewProgress(1, time.Second, clock)
+
+		var final ProgressReport
+
+		// the flip is reported to observers once Done() is closed, but
+		// before Step() returns
+		previous := SetObserver(observerFunc(func(e LatchEvent) {
+
+			if e.Kind == LatchFlipped {
+
+				final = progress.Report()
+			}
+		}))
+
+		defer SetObserver(previous)
+
+		clock.Advance(time.Second)
+
+		progress.Step()
+
+		require.True(t, final.Done)
+		require.Equal(t, time.Second, final.Elapsed)
+	})
+
+	t.Run("NumWaiters()", func(t *testing.T) {
+
+		progress := NewProgress(1, 0, nil)
+
+		var wg sync.WaitGroup
+
+		wg.Go(func() {
+
+			progress.Wait()
+		})
+
+		for progress.NumWaiters() == 0 {
+
+			time.Sleep(time.Millisecond)
+		}
+
+		progress.Step()
+
+		wg.Wait()
+
+		require.Equal(t, 0, progress.NumWaiters())
+	})
+
+	t.Run("concurrent steps", func(t *testing.T) {
+
+		progress := NewProgress(1000, 0, nil)
+
+		var wg sync.WaitGroup
+
+		for i := 0; i != 10; i++ {
+
+			wg.Go(func() {
+
+				for j := 0; j != 100; j++ {
+
+					progress.Step()
+				}
+			})
+		}
+
+		progress.Wait()
+
+		wg.Wait()
+
+		require.Equal(t, int64(1000), progress.Report().Completed)
+	})
+
+	t.Run("ReportEvery()", func(t *testing.T) {
+
+		clock := &synctesting.FakeClock{}
+
+		progress := NewProgress(3, time.Second, clock)
+
+		reports := make(chan ProgressReport, 10)
+
+		var wg sync.WaitGroup
+		var err error
+
+		wg.Go(func() {
+
+			err = progress.ReportEvery(context.Background(), time.Second, func(report ProgressReport) {
+
+				reports <- report
+			})
+		})
+
+		for i := 0; i != 3; i++ {
+
+			require.True(t, clock.AwaitTimers(1, time.Second))
+
+			progress.Step()
+
+			clock.Advance(time.Second)
+
+			report := <-reports
+
+			require.Equal(t, int64(i+1), report.Completed)
+		}
+
+		wg.Wait()
+
+		require.Nil(t, err)
+	})
+
+	t.Run("ReportEvery() returns when ctx is done", func(t *testing.T) {
+
+		progress := NewProgress(3, 0, &synctesting.FakeClock{})
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		cancel()
+
+		err := progress.ReportEvery(ctx, time.Second, func(ProgressReport) {})
+
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("Reports() delivers a final report and closes", func(t *testing.T) {
+
+		progress := NewProgress(2, 0, &synctesting.FakeClock{})
+
+		ch := progress.Reports(context.Background(), time.Hour)
+
+		progress.Step()
+		progress.Step()
+
+		var last ProgressReport
+
+		for report := range ch {
+
+			last = report
+		}
+
+		require.True(t, last.Done)
+		require.Equal(t, int64(2), last.Completed)
+	})
+
+	t.Run("Reports() with non-positive interval panics", func(t *testing.T) {
+
+		progress := NewProgress(1, 0, nil)
+
+		require.Panics(t, func() {
+
+			_ = progress.Reports(context.Background(), 0)
+		})
+	})
+}
@@ -0,0 +1,431 @@
+// Copyright 2019-2026 Matthew Wilson and Synesis Information Systems. All
+// rights reserved. Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+ * Created: 19th October 2026
+ * Updated:
+ */
+
+// Definition of a bounded lock-free multi-producer/multi-consumer queue.
+
+package sync
+
+import (
+	"context"
+	"errors"
+	"math/bits"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	// Returned by RingBuffer.Push() when the ring buffer is closed, and by
+	// RingBuffer.Pop() when it is closed and drained.
+	ErrRingBufferClosed = errors.New("ring buffer is closed")
+
+	errRingBufferCapacityMustBePositive = errors.New("ring buffer capacity must be positive")
+)
+
+// The bit of the enqueue position that indicates that the ring buffer is
+// closed, so that closing and claiming a slot are mutually atomic.
+const ringBufferClosedBit = uint64(1) << 63
+
+type _ringSlot[T any] struct {
+	seq   uint64
+	value T
+}
+
+// Padding that places the fields on either side of it on different pairs
+// of cache lines.
+type _ringPad [128]byte
+
+// A signal upon which goroutines may wait for a change of state.
+//
+// A waiter obtains the channel and then re-checks the state before waiting
+// on the channel; a notifier changes the state and then closes the channel
+// if there are waiters. Since the count of waiters and the state are both
+// changed and read atomically, either the waiter sees the change or the
+// notifier sees the waiter, so no wakeup is lost. All waiters are woken.
+type _ringSignal struct {
+	waiters int64
+	mx      sync.Mutex
+	ch      chan struct{}
+}
+
+func (s *_ringSignal) prepare() <-chan struct{} {
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	atomic.AddInt64(&s.waiters, 1)
+
+	if s.ch == nil {
+
+		s.ch = make(chan struct{})
+	}
+
+	return s.ch
+}
+
+func (s *_ringSignal) cancel() {
+
+	atomic.AddInt64(&s.waiters, -1)
+}
+
+func (s *_ringSignal) notify() {
+
+	if atomic.LoadInt64(&s.waiters) != 0 {
+
+		s.notifySlow()
+	}
+}
+
+func (s *_ringSignal) notifySlow() {
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if s.ch != nil {
+
+		close(s.ch)
+
+		s.ch = nil
+	}
+}
+
+// A bounded first-in-first-out queue of values of type T that may be
+// pushed and popped, without locks, by multiple concurrent producers and
+// consumers.
+//
+// Each slot carries a sequence counter that indicates whether it is ready
+// to be pushed into or popped from at a given position (after Dmitry
+// Vyukov's bounded MPMC queue); producers and consumers claim positions by
+// compare-and-swap on separate counters, so a producer and a consumer
+// contend only when the ring buffer is almost empty or almost full.
+//
+// Push() and Pop() block while the ring buffer is full or empty,
+// respectively; TryPush() and TryPop() do not. Once closed, a ring buffer
+// accepts no more values, but those that it holds may still be popped.
+type RingBuffer[T any] struct {
+	_        _ringPad
+	enqueue  uint64 // the next position to be pushed, and ringBufferClosedBit
+	_        _ringPad
+	dequeue  uint64 // the next position to be popped
+	_        _ringPad
+	mask     uint64
+	slots    []_ringSlot[T]
+	notEmpty _ringSignal
+	notFull  _ringSignal
+	waiting  _waiterCount
+}
+
+// Creates a new RingBuffer whose capacity is at least capacity, being
+// rounded up to a power of two, of at least 2.
+//
+// Preconditions:
+// - capacity > 0;
+func NewRingBuffer[T any](capacity int) *RingBuffer[T] {
+
+	if capacity <= 0 {
+
+		panic(errRingBufferCapacityMustBePositive)
+	}
+
+	// a single slot cannot distinguish a full ring from an empty one
+	n := max(2, 1<<bits.Len(uint(capacity-1)))
+
+	r := &RingBuffer[T]{
+		mask:  uint64(n - 1),
+		slots: make([]_ringSlot[T], n),
+	}
+
+	for i := range r.slots {
+
+		r.slots[i].seq = uint64(i)
+	}
+
+	return r
+}
+
+type _ringResult int
+
+const (
+	ringDone _ringResult = iota
+	ringWouldBlock
+	ringClosed
+)
+
+func (r *RingBuffer[T]) tryPush(v T) _ringResult {
+
+	pos := atomic.LoadUint64(&r.enqueue)
+
+	for {
+
+		if pos&ringBufferClosedBit != 0 {
+
+			return ringClosed
+		}
+
+		slot := &r.slots[pos&r.mask]
+
+		switch seq := atomic.LoadUint64(&slot.seq); {
+		case seq == pos:
+
+			if atomic.CompareAndSwapUint64(&r.enqueue, pos, pos+1) {
+
+				slot.value = v
+
+				atomic.StoreUint64(&slot.seq, pos+1)
+
+				r.notEmpty.notify()
+
+				return ringDone
+			}
+
+			pos = atomic.LoadUint64(&r.enqueue)
+		case int64(seq-pos) < 0:
+
+			// the slot has not yet been popped from the previous lap
+			return ringWouldBlock
+		default:
+
+			pos = atomic.LoadUint64(&r.enqueue)
+		}
+	}
+}
+
+func (r *RingBuffer[T]) tryPop() (v T, result _ringResult) {
+
+	pos := atomic.LoadUint64(&r.dequeue)
+
+	for {
+
+		slot := &r.slots[pos&r.mask]
+
+		switch seq := atomic.LoadUint64(&slot.seq); {
+		case seq == pos+1:
+
+			if atomic.CompareAndSwapUint64(&r.dequeue, pos, pos+1) {
+
+				var zero T
+
+				v = slot.value
+				slot.value = zero
+
+				atomic.StoreUint64(&slot.seq, pos+r.mask+1)
+
+				r.notFull.notify()
+
+				return v, ringDone
+			}
+
+			pos = atomic.LoadUint64(&r.dequeue)
+		case int64(seq-(pos+1)) < 0:
+
+			// the slot has not yet been pushed, although it may have been
+			// claimed by a producer before the ring buffer was closed
+			if enqueue := atomic.LoadUint64(&r.enqueue); enqueue == pos|ringBufferClosedBit {
+
+				return v, ringClosed
+			}
+
+			return v, ringWouldBlock
+		default:
+
+			pos = atomic.LoadUint64(&r.dequeue)
+		}
+	}
+}
+
+// Pushes v onto the ring buffer if there is room and it is not closed,
+// without blocking.
+//
+// Since positions are claimed before values are transferred, the ring
+// buffer may also appear full while the value at the position to be pushed
+// is still being popped by another goroutine.
+//
+// Returns:
+// true if v was pushed; false if the ring buffer is full or closed
+func (r *RingBuffer[T]) TryPush(v T) bool {
+
+	return r.tryPush(v) == ringDone
+}
+
+// Pops the oldest value from the ring buffer if it is not empty, without
+// blocking.
+//
+// Since positions are claimed before values are transferred, the ring
+// buffer may also appear empty while the oldest value is still being
+// pushed by another goroutine, even if later values have been pushed.
+//
+// Returns:
+// the value and true; or the zero value and false if the ring buffer is
+// empty
+func (r *RingBuffer[T]) TryPop() (v T, ok bool) {
+
+	v, result := r.tryPop()
+
+	return v, result == ringDone
+}
+
+// Pushes v onto the ring buffer, blocking the caller while it is full
+// until there is room, it is closed, or ctx is done.
+//
+// Returns:
+// nil if v was pushed; ErrRingBufferClosed if the ring buffer is closed;
+// ctx.Err() otherwise
+func (r *RingBuffer[T]) Push(ctx context.Context, v T) error {
+
+	for {
+
+		switch r.tryPush(v) {
+		case ringDone:
+
+			return nil
+		case ringClosed:
+
+			return ErrRingBufferClosed
+		}
+
+		ch := r.notFull.prepare()
+
+		switch r.tryPush(v) {
+		case ringDone:
+
+			r.notFull.cancel()
+
+			return nil
+		case ringClosed:
+
+			r.notFull.cancel()
+
+			return ErrRingBufferClosed
+		}
+
+		token := r.waiting.begin()
+
+		select {
+		case <-ch:
+
+			r.waiting.end(token)
+
+			r.notFull.cancel()
+		case <-ctx.Done():
+
+			r.waiting.end(token)
+
+			r.notFull.cancel()
+
+			return ctx.Err()
+		}
+	}
+}
+
+// Pops the oldest value from the ring buffer, blocking the caller while it
+// is empty until a value is pushed, it is closed, or ctx is done.
+//
+// Returns:
+// the value and nil; or the zero value and ErrRingBufferClosed if the
+// ring buffer is closed and drained; or the zero value and ctx.Err()
+func (r *RingBuffer[T]) Pop(ctx context.Context) (v T, err error) {
+
+	for {
+
+		var result _ringResult
+
+		switch v, result = r.tryPop(); result {
+		case ringDone:
+
+			return
+		case ringClosed:
+
+			return v, ErrRingBufferClosed
+		}
+
+		ch := r.notEmpty.prepare()
+
+		switch v, result = r.tryPop(); result {
+		case ringDone:
+
+			r.notEmpty.cancel()
+
+			return
+		case ringClosed:
+
+			r.notEmpty.cancel()
+
+			return v, ErrRingBufferClosed
+		}
+
+		token := r.waiting.begin()
+
+		select {
+		case <-ch:
+
+			r.waiting.end(token)
+
+			r.notEmpty.cancel()
+		case <-ctx.Done():
+
+			r.waiting.end(token)
+
+			r.notEmpty.cancel()
+
+			return v, ctx.Err()
+		}
+	}
+}
+
+// Closes the ring buffer, so that it accepts no more values. Values that
+// it holds may still be popped, after which Pop() fails with
+// ErrRingBufferClosed. Blocked producers and consumers are woken.
+//
+// Returns:
+// true if this call closed the ring buffer; false if it was already
+// closed
+func (r *RingBuffer[T]) Close() (closed bool) {
+
+	closed = atomic.OrUint64(&r.enqueue, ringBufferClosedBit)&ringBufferClosedBit == 0
+
+	if closed {
+
+		r.notFull.notifySlow()
+		r.notEmpty.notifySlow()
+	}
+
+	return
+}
+
+// Indicates whether the ring buffer has been closed.
+func (r *RingBuffer[T]) IsClosed() bool {
+
+	return atomic.LoadUint64(&r.enqueue)&ringBufferClosedBit != 0
+}
+
+// Obtains the number of values in the ring buffer, including any being
+// pushed or popped concurrently.
+func (r *RingBuffer[T]) Len() int {
+
+	dequeue := atomic.LoadUint64(&r.dequeue)
+	enqueue := atomic.LoadUint64(&r.enqueue) &^ ringBufferClosedBit
+
+	if enqueue <= dequeue {
+
+		return 0
+	}
+
+	return int(min(enqueue-dequeue, r.mask+1))
+}
+
+// Obtains the capacity of the ring buffer.
+func (r *RingBuffer[T]) Cap() int {
+
+	return len(r.slots)
+}
+
+// Obtains the number of goroutines currently blocked in Push() or Pop().
+func (r *RingBuffer[T]) NumWaiters() int {
+
+	return int(r.waiting.load())
+}
@@ -0,0 +1,339 @@
+package sync_test
+
+import (
+	. "github.com/synesissoftware/syngo/sync"
+
+	"github.com/synesissoftware/syngo/sync/synctesting"
+
+	"github.com/stretchr/testify/require"
+
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_RingBuffer(t *testing.T) {
+
+	t.Run("NewRingBuffer() panics on a non-positive capacity", func(t *testing.T) {
+
+		for _, capacity := range []int{-1, 0} {
+
+			require.Panics(t, func() {
+
+				_ = NewRingBuffer[int](capacity)
+			})
+		}
+	})
+
+	t.Run("the capacity is rounded up to a power of two of at least 2", func(t *testing.T) {
+
+		require.Equal(t, 2, NewRingBuffer[int](1).Cap())
+		require.Equal(t, 2, NewRingBuffer[int](2).Cap())
+		require.Equal(t, 8, NewRingBuffer[int](5).Cap())
+		require.Equal(t, 1024, NewRingBuffer[int](1024).Cap())
+	})
+
+	t.Run("TryPush() and TryPop() are first-in-first-out and bounded", func(t *testing.T) {
+
+		r := NewRingBuffer[string](4)
+
+		_, ok := r.TryPop()
+
+		require.False(t, ok)
+
+		// several laps
+		for lap := range 3 {
+
+			for _, s := range []string{"a", "b", "c", "d"} {
+
+				require.True(t, r.TryPush(s), "lap %d", lap)
+			}
+
+			require.False(t, r.TryPush("e"))
+			require.Equal(t, 4, r.Len())
+
+			for _, s := range []string{"a", "b", "c", "d"} {
+
+				v, ok := r.TryPop()
+
+				require.True(t, ok)
+				require.Equal(t, s, v)
+			}
+
+			require.Equal(t, 0, r.Len())
+		}
+	})
+
+	t.Run("Close() prevents pushes but allows draining", func(t *testing.T) {
+
+		r := NewRingBuffer[int](4)
+
+		require.True(t, r.TryPush(1))
+		require.True(t, r.TryPush(2))
+
+		require.True(t, r.Close())
+		require.False(t, r.Close())
+		require.True(t, r.IsClosed())
+
+		require.False(t, r.TryPush(3))
+		require.ErrorIs(t, r.Push(context.Background(), 3), ErrRingBufferClosed)
+		require.Equal(t, 2, r.Len())
+
+		v, err := r.Pop(context.Background())
+
+		require.Nil(t, err)
+		require.Equal(t, 1, v)
+
+		v, ok := r.TryPop()
+
+		require.True(t, ok)
+		require.Equal(t, 2, v)
+
+		_, err = r.Pop(context.Background())
+
+		require.ErrorIs(t, err, ErrRingBufferClosed)
+	})
+
+	t.Run("Pop() blocks until a value is pushed", func(t *testing.T) {
+
+		synctesting.VerifyNoLeaks(t)
+
+		r := NewRingBuffer[int](2)
+
+		got := make(chan int)
+
+		go func() {
+
+			v, _ := r.Pop(context.Background())
+
+			got <- v
+		}()
+
+		select {
+		case <-got:
+
+			require.Fail(t, "Pop() returned from an empty ring buffer")
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		require.Nil(t, r.Push(context.Background(), 42))
+		require.Equal(t, 42, <-got)
+	})
+
+	t.Run("Push() blocks until there is room", func(t *testing.T) {
+
+		synctesting.VerifyNoLeaks(t)
+
+		r := NewRingBuffer[int](2)
+
+		require.True(t, r.TryPush(1))
+		require.True(t, r.TryPush(2))
+
+		pushed := make(chan error)
+
+		go func() {
+
+			pushed <- r.Push(context.Background(), 3)
+		}()
+
+		select {
+		case <-pushed:
+
+			require.Fail(t, "Push() returned to a full ring buffer")
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		v, ok := r.TryPop()
+
+		require.True(t, ok)
+		require.Equal(t, 1, v)
+		require.Nil(t, <-pushed)
+	})
+
+	t.Run("Push() and Pop() return the context's error", func(t *testing.T) {
+
+		r := NewRingBuffer[int](2)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := r.Pop(ctx)
+
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		require.True(t, r.TryPush(1))
+		require.True(t, r.TryPush(2))
+
+		require.ErrorIs(t, r.Push(ctx, 3), context.DeadlineExceeded)
+	})
+
+	t.Run("Close() wakes blocked producers and consumers", func(t *testing.T) {
+
+		synctesting.VerifyNoLeaks(t)
+
+		empty := NewRingBuffer[int](2)
+		full := NewRingBuffer[int](2)
+
+		require.True(t, full.TryPush(1))
+		require.True(t, full.TryPush(2))
+
+		errs := make(chan error, 2)
+
+		go func() {
+
+			_, err := empty.Pop(context.Background())
+
+			errs <- err
+		}()
+
+		go func() {
+
+			errs <- full.Push(context.Background(), 3)
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+
+		empty.Close()
+		full.Close()
+
+		require.ErrorIs(t, <-errs, ErrRingBufferClosed)
+		require.ErrorIs(t, <-errs, ErrRingBufferClosed)
+		require.Equal(t, 2, full.Len())
+	})
+
+	t.Run("concurrent producers and consumers transfer every value once", func(t *testing.T) {
+
+		synctesting.VerifyNoLeaks(t)
+
+		const numProducers = 4
+		const numConsumers = 4
+		const numValues = 10_000
+
+		r := NewRingBuffer[int](16)
+
+		var sum int64
+		var count int64
+
+		var consumers sync.WaitGroup
+
+		for range numConsumers {
+
+			consumers.Go(func() {
+
+				for {
+
+					v, err := r.Pop(context.Background())
+
+					if err != nil {
+
+						return
+					}
+
+					atomic.AddInt64(&sum, int64(v))
+					atomic.AddInt64(&count, 1)
+				}
+			})
+		}
+
+		var producers sync.WaitGroup
+
+		for p := range numProducers {
+
+			producers.Go(func() {
+
+				for i := range numValues {
+
+					if err := r.Push(context.Background(), p*numValues+i); err != nil {
+
+						panic(err)
+					}
+				}
+			})
+		}
+
+		producers.Wait()
+
+		r.Close()
+
+		consumers.Wait()
+
+		const n = numProducers * numValues
+
+		require.Equal(t, int64(n), count)
+		require.Equal(t, int64(n*(n-1)/2), sum)
+	})
+}
+
+func Benchmark_RingBuffer_vs_channel(b *testing.B) {
+
+	const capacity = 1024
+
+	b.Run("RingBuffer TryPush/TryPop", func(b *testing.B) {
+
+		r := NewRingBuffer[int](capacity)
+
+		b.RunParallel(func(pb *testing.PB) {
+
+			for pb.Next() {
+
+				if r.TryPush(1) {
+
+					r.TryPop()
+				}
+			}
+		})
+	})
+
+	b.Run("RingBuffer Push/Pop", func(b *testing.B) {
+
+		r := NewRingBuffer[int](capacity)
+
+		ctx := context.Background()
+
+		b.RunParallel(func(pb *testing.PB) {
+
+			for pb.Next() {
+
+				_ = r.Push(ctx, 1)
+				_, _ = r.Pop(ctx)
+			}
+		})
+	})
+
+	b.Run("channel select/default", func(b *testing.B) {
+
+		ch := make(chan int, capacity)
+
+		b.RunParallel(func(pb *testing.PB) {
+
+			for pb.Next() {
+
+				select {
+				case ch <- 1:
+
+					select {
+					case <-ch:
+					default:
+					}
+				default:
+				}
+			}
+		})
+	})
+
+	b.Run("channel send/receive", func(b *testing.B) {
+
+		ch := make(chan int, capacity)
+
+		b.RunParallel(func(pb *testing.PB) {
+
+			for pb.Next() {
+
+				ch <- 1
+				<-ch
+			}
+		})
+	})
+}
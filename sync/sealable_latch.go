@@ -0,0 +1,276 @@
+// Copyright 2019-2026 Matthew Wilson and Synesis Information Systems. All
+// rights reserved. Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+ * Created: 19th October 2026
+ * Updated:
+ */
+
+// Definition of a down-latch whose count may be increased until sealed.
+
+package sync
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+var (
+	// Returned by SealableDownLatch.Add() when the latch has been sealed.
+	ErrLatchSealed = errors.New("latch is sealed")
+
+	errSealableDownLatchInitialValueMustNotBeLessThanThreshold = errors.New("initial value must not be less than the threshold")
+	errSealableDownLatchAddMustNotBeNegative                   = errors.New("latch may not be added to by a negative amount")
+	errSealableDownLatchStepBelowThreshold                     = errors.New("latch stepped below its threshold")
+)
+
+// The largest count that may be outstanding in a SealableDownLatch.
+const maxSealableDistance int64 = MaxLatchDistance >> 1
+
+// A unidirectional latch that counts down to a threshold, whose count may
+// be increased - as work is discovered - until it is sealed, and that may
+// be operated safely by multiple concurrent goroutines.
+//
+// The latch flips only when it is both sealed and at its threshold, so,
+// unlike sync.WaitGroup, there is no race between adding to the count and
+// waiting: an Add() that follows Seal() fails with ErrLatchSealed, rather
+// than being lost.
+//
+// The sealed state and the outstanding count are held in a single word,
+// changed by compare-and-swap.
+//
+// Steps, and a call to Seal() that flips the latch, are reported to any
+// observer (see SetObserver()); calls to Add(), and to Seal() that do not
+// flip it, are not.
+type SealableDownLatch struct {
+	_latchNotifier
+	_observed
+	word      int64 // outstanding count << 1 | sealed
+	threshold int64
+}
+
+// Creates a new SealableDownLatch, unsealed, with initialValue -
+// threshold outstanding.
+//
+// Preconditions:
+// - initialValue >= threshold;
+// - initialValue - threshold <= MaxLatchDistance / 2;
+func NewSealableDownLatch(initialValue, threshold int64) SealableDownLatch {
+
+	if initialValue < threshold {
+
+		panic(errSealableDownLatchInitialValueMustNotBeLessThanThreshold)
+	}
+
+	// the difference is exact as uint64, even where it overflows int64
+	if uint64(initialValue)-uint64(threshold) > uint64(maxSealableDistance) {
+
+		panic(errLatchDistanceExceedsMaximum)
+	}
+
+	observeCreated("SealableDownLatch", initialValue)
+
+	return SealableDownLatch{
+		word:      (initialValue - threshold) << 1,
+		threshold: threshold,
+	}
+}
+
+// Increases the count by n.
+//
+// Returns:
+// nil if the count was increased; ErrLatchSealed if the latch has been
+// sealed
+//
+// Preconditions:
+// - n >= 0;
+// - the outstanding count, after adding n, <= MaxLatchDistance / 2;
+func (l *SealableDownLatch) Add(n int64) error {
+
+	if n < 0 {
+
+		panic(errSealableDownLatchAddMustNotBeNegative)
+	}
+
+	for {
+
+		word := atomic.LoadInt64(&l.word)
+
+		if word&1 != 0 {
+
+			return ErrLatchSealed
+		}
+
+		if n > maxSealableDistance-word>>1 {
+
+			panic(errLatchDistanceExceedsMaximum)
+		}
+
+		if atomic.CompareAndSwapInt64(&l.word, word, word+n<<1) {
+
+			return nil
+		}
+	}
+}
+
+// Seals the latch, so that its count may no longer be increased. If the
+// latch is at its threshold, it flips.
+//
+// Returns:
+// true if the latch was flipped; false otherwise
+func (l *SealableDownLatch) Seal() (flipped bool) {
+
+	for {
+
+		word := atomic.LoadInt64(&l.word)
+
+		if word&1 != 0 {
+
+			return false
+		}
+
+		if atomic.CompareAndSwapInt64(&l.word, word, word|1) {
+
+			if word == 0 {
+
+				flipped = true
+
+				l._latchNotifier.fire()
+
+				if o := l._observed.load(); o != nil {
+
+					observeStep(o, "SealableDownLatch", l, true, true, l.threshold)
+				}
+			}
+
+			return
+		}
+	}
+}
+
+// Steps the latch down by one; no effect if already latched.
+//
+// Returns:
+// flipped - true if this step latched the latch;
+// isLatched - true if the latch is latched;
+// newCount - the count after the step;
+//
+// Preconditions:
+// - the latch is sealed, or its count is above the threshold;
+func (l *SealableDownLatch) Step() (flipped, isLatched bool, newCount int64) {
+
+	flipped, isLatched, newCount = l.step()
+
+	if o := l._observed.load(); o != nil {
+
+		observeStep(o, "SealableDownLatch", l, flipped, isLatched, newCount)
+	}
+
+	return
+}
+
+func (l *SealableDownLatch) step() (flipped, isLatched bool, newCount int64) {
+
+	for {
+
+		word := atomic.LoadInt64(&l.word)
+
+		switch word {
+		case 1:
+
+			return false, true, l.threshold
+		case 0:
+
+			panic(errSealableDownLatchStepBelowThreshold)
+		}
+
+		next := word - 2
+
+		if atomic.CompareAndSwapInt64(&l.word, word, next) {
+
+			if next == 1 {
+
+				flipped = true
+				isLatched = true
+
+				l._latchNotifier.fire()
+			}
+
+			newCount = l.threshold + next>>1
+
+			return
+		}
+	}
+}
+
+// Obtains the current value of the latch, without changing its state.
+func (l *SealableDownLatch) Load() (isLatched bool, count int64) {
+
+	word := atomic.LoadInt64(&l.word)
+
+	isLatched = word == 1
+	count = l.threshold + word>>1
+
+	return
+}
+
+// Indicates whether the latch has been sealed.
+func (l *SealableDownLatch) IsSealed() bool {
+
+	return atomic.LoadInt64(&l.word)&1 != 0
+}
+
+// Indicates whether the latch has latched, without changing its state.
+func (l *SealableDownLatch) IsLatched() bool {
+
+	return atomic.LoadInt64(&l.word) == 1
+}
+
+// Obtains a channel that is closed when the latch latches.
+func (l *SealableDownLatch) Done() <-chan struct{} {
+
+	return l._latchNotifier.done()
+}
+
+// Blocks the caller until the latch latches.
+func (l *SealableDownLatch) Wait() {
+
+	if o := l._observed.load(); o != nil {
+
+		observeWait(o, "SealableDownLatch", l, &l._latchNotifier, nil)
+
+		return
+	}
+
+	l._latchNotifier.wait()
+}
+
+// Blocks the caller until the latch latches or ctx is done.
+//
+// Returns:
+// nil if the latch latched; ctx.Err() otherwise
+func (l *SealableDownLatch) WaitContext(ctx context.Context) error {
+
+	if o := l._observed.load(); o != nil {
+
+		return observeWait(o, "SealableDownLatch", l, &l._latchNotifier, ctx)
+	}
+
+	return l._latchNotifier.waitContext(ctx)
+}
+
+// Installs o as the observer of the instance, in place of any global
+// observer. Passing nil reverts to the global observer.
+func (l *SealableDownLatch) SetObserver(o Observer) {
+
+	l._observed.set(o)
+}
+
+// Obtains the number of goroutines currently blocked in Wait() or
+// WaitContext().
+func (l *SealableDownLatch) NumWaiters() int {
+
+	return int(l._latchNotifier.numWaiters())
+}
@@ -0,0 +1,319 @@
+package sync_test
+
+import (
+	. "github.com/synesissoftware/syngo/sync"
+
+	"github.com/synesissoftware/syngo/sync/synctesting"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"context"
+	"math"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var int64Boundaries = []int64{
+	math.MinInt64,
+	math.MinInt64 + 1,
+	-2,
+	-1,
+	0,
+	1,
+	2,
+	math.MaxInt64/2 - 1,
+	math.MaxInt64 / 2,
+	math.MaxInt64/2 + 1,
+	math.MaxInt64 - 1,
+	math.MaxInt64,
+}
+
+// Obtains the distance from threshold to initialValue, which may exceed
+// the range of int64.
+func int64Distance(initialValue, threshold int64) *big.Int {
+
+	return new(big.Int).Sub(big.NewInt(initialValue), big.NewInt(threshold))
+}
+
+func Test_SealableDownLatch(t *testing.T) {
+
+	t.Run("NewSealableDownLatch() panics on invalid arguments", func(t *testing.T) {
+
+		for _, args := range [][2]int64{
+			{0, 1},
+			{MaxLatchDistance, 0},
+			{MaxLatchDistance/2 + 1, 0},
+		} {
+
+			require.Panics(t, func() {
+
+				_ = NewSealableDownLatch(args[0], args[1])
+			})
+		}
+
+		require.NotPanics(t, func() {
+
+			_ = NewSealableDownLatch(MaxLatchDistance/2, 0)
+		})
+	})
+
+	t.Run("NewSealableDownLatch() panics unless the distance is in range, at every boundary", func(t *testing.T) {
+
+		maxDistance := big.NewInt(MaxLatchDistance / 2)
+
+		for _, initial := range int64Boundaries {
+
+			for _, threshold := range int64Boundaries {
+
+				distance := int64Distance(initial, threshold)
+
+				if distance.Sign() < 0 || distance.Cmp(maxDistance) > 0 {
+
+					require.Panics(t, func() {
+
+						_ = NewSealableDownLatch(initial, threshold)
+					}, "[%d, %d)", initial, threshold)
+
+					continue
+				}
+
+				latch := NewSealableDownLatch(initial, threshold)
+
+				isLatched, count := latch.Load()
+
+				require.False(t, isLatched)
+				require.Equal(t, initial, count)
+
+				require.Equal(t, distance.Sign() == 0, latch.Seal(), "[%d, %d)", initial, threshold)
+				require.Equal(t, distance.Sign() == 0, latch.IsLatched(), "[%d, %d)", initial, threshold)
+			}
+		}
+	})
+
+	t.Run("does not flip at the threshold until sealed", func(t *testing.T) {
+
+		latch := NewSealableDownLatch(2, 0)
+
+		flipped, isLatched, count := latch.Step()
+
+		require.False(t, flipped)
+		require.False(t, isLatched)
+		require.Equal(t, int64(1), count)
+
+		flipped, isLatched, count = latch.Step()
+
+		require.False(t, flipped)
+		require.False(t, isLatched)
+		require.Equal(t, int64(0), count)
+
+		require.False(t, latch.IsLatched())
+		require.False(t, latch.IsSealed())
+
+		require.True(t, latch.Seal())
+
+		require.True(t, latch.IsLatched())
+		require.True(t, latch.IsSealed())
+		require.False(t, latch.Seal())
+	})
+
+	t.Run("Add() increases the count until sealed", func(t *testing.T) {
+
+		latch := NewSealableDownLatch(10, 10)
+
+		require.Nil(t, latch.Add(3))
+
+		isLatched, count := latch.Load()
+
+		require.False(t, isLatched)
+		require.Equal(t, int64(13), count)
+
+		latch.Step()
+
+		require.Nil(t, latch.Add(1))
+
+		require.False(t, latch.Seal())
+
+		require.ErrorIs(t, latch.Add(1), ErrLatchSealed)
+
+		latch.Step()
+		latch.Step()
+
+		flipped, isLatched, count := latch.Step()
+
+		require.True(t, flipped)
+		require.True(t, isLatched)
+		require.Equal(t, int64(10), count)
+
+		flipped, isLatched, count = latch.Step()
+
+		require.False(t, flipped)
+		require.True(t, isLatched)
+		require.Equal(t, int64(10), count)
+
+		require.ErrorIs(t, latch.Add(1), ErrLatchSealed)
+	})
+
+	t.Run("Add() panics on invalid arguments", func(t *testing.T) {
+
+		latch := NewSealableDownLatch(0, 0)
+
+		require.Panics(t, func() {
+
+			_ = latch.Add(-1)
+		})
+
+		require.Nil(t, latch.Add(MaxLatchDistance/2))
+
+		require.Panics(t, func() {
+
+			_ = latch.Add(1)
+		})
+	})
+
+	t.Run("Step() below the threshold while unsealed panics", func(t *testing.T) {
+
+		latch := NewSealableDownLatch(0, 0)
+
+		require.Panics(t, func() {
+
+			latch.Step()
+		})
+	})
+
+	t.Run("waiting before work is added is not racy", func(t *testing.T) {
+
+		latch := NewSealableDownLatch(0, 0)
+
+		var wg sync.WaitGroup
+
+		wg.Go(func() {
+
+			latch.Wait()
+		})
+
+		// in a sync.WaitGroup, a Wait() at zero would already have returned
+		synctesting.RequireNeverFlips(t, &latch, 10*time.Millisecond)
+
+		require.Nil(t, latch.Add(1))
+
+		latch.Step()
+
+		synctesting.RequireNeverFlips(t, &latch, time.Millisecond)
+
+		latch.Seal()
+
+		synctesting.RequireFlipsWithin(t, &latch, time.Second)
+
+		wg.Wait()
+
+		require.Nil(t, latch.WaitContext(context.Background()))
+	})
+
+	t.Run("work discovered while earlier work completes", func(t *testing.T) {
+
+		latch := NewSealableDownLatch(0, 0)
+
+		var numDone atomic.Int64
+		var numFlips atomic.Int64
+
+		var wg, workers sync.WaitGroup
+
+		wg.Go(func() {
+
+			// discovers work in batches, each completing quickly, so that
+			// the count repeatedly returns to the threshold before sealing
+			for batch := 0; batch != 10; batch++ {
+
+				assert.Nil(t, latch.Add(10))
+
+				for i := 0; i != 10; i++ {
+
+					workers.Go(func() {
+
+						numDone.Add(1)
+
+						if flipped, _, _ := latch.Step(); flipped {
+
+							numFlips.Add(1)
+						}
+					})
+				}
+
+				time.Sleep(time.Millisecond)
+			}
+
+			if latch.Seal() {
+
+				numFlips.Add(1)
+			}
+		})
+
+		latch.Wait()
+
+		require.Equal(t, int64(100), numDone.Load())
+
+		wg.Wait()
+		workers.Wait()
+
+		require.Equal(t, int64(1), numFlips.Load())
+	})
+
+	t.Run("composes with AllOf()", func(t *testing.T) {
+
+		latch := NewSealableDownLatch(0, 0)
+
+		composite := AllOf(&latch)
+
+		require.False(t, composite.IsLatched())
+
+		latch.Seal()
+
+		require.True(t, composite.IsLatched())
+	})
+
+	t.Run("per-instance observer", func(t *testing.T) {
+
+		var observer recordingObserver
+
+		latch := NewSealableDownLatch(1, 0)
+
+		latch.SetObserver(&observer)
+
+		require.Nil(t, latch.Add(1))
+
+		latch.Step()
+
+		require.False(t, latch.Seal())
+
+		latch.Step()
+		latch.Step()
+		latch.Wait()
+
+		require.Equal(t, []LatchEventKind{LatchStepped, LatchFlipped, LatchSteppedAfterLatched, LatchWaited}, observer.kinds())
+		require.Equal(t, "SealableDownLatch", observer.events[0].Type)
+		require.Same(t, &latch, observer.events[0].Latch)
+		require.Equal(t, int64(1), observer.events[0].Count)
+	})
+
+	t.Run("a flipping Seal() is reported", func(t *testing.T) {
+
+		var global recordingObserver
+
+		previous := SetObserver(&global)
+
+		defer SetObserver(previous)
+
+		latch := NewSealableDownLatch(5, 5)
+
+		require.True(t, latch.Seal())
+		require.False(t, latch.Seal())
+
+		require.Equal(t, []LatchEventKind{LatchCreated, LatchFlipped}, global.kinds())
+		require.Equal(t, int64(5), global.events[0].Count)
+		require.Equal(t, int64(5), global.events[1].Count)
+	})
+}
@@ -0,0 +1,231 @@
+// Copyright 2019-2026 Matthew Wilson and Synesis Information Systems. All
+// rights reserved. Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+ * Created: 19th October 2026
+ * Updated:
+ */
+
+// Definition of a sequence lock, for read-mostly shared values.
+
+package sync
+
+import (
+	"reflect"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+const wordSize = unsafe.Sizeof(uintptr(0))
+
+// Storage for a SeqLock value, padded to a whole number of words so that it
+// may be copied word-by-word.
+type _seqBox[T any] struct {
+	value T
+	_     [0]uintptr
+}
+
+// A sequence lock protecting a value of type T: writers are serialised,
+// and bump a sequence counter before and after each update; readers take
+// no lock, instead copying the value optimistically and retrying if the
+// sequence shows that a write intervened.
+//
+// The value is copied word-by-word with atomic operations (pointer words
+// with atomic pointer operations, so that the garbage collector is
+// informed), which keeps readers free of data races as far as the race
+// detector is concerned, and means that a reader never observes a torn
+// value. Readers do not block writers, and so may be starved by a
+// sustained stream of writes.
+//
+// SeqLock is suited to small values that are read very much more often
+// than they are written. A SeqLock must be created by NewSeqLock(), and
+// must not be copied.
+type SeqLock[T any] struct {
+	seq      UpCounter
+	mx       sync.Mutex
+	box      _seqBox[T]
+	pointers []bool // whether each word of box is a pointer
+}
+
+// Creates a new SeqLock holding the given initial value.
+func NewSeqLock[T any](initialValue T) *SeqLock[T] {
+
+	s := &SeqLock[T]{
+		seq: NewUpCounter(0),
+		box: _seqBox[T]{
+			value: initialValue,
+		},
+	}
+
+	s.pointers = make([]bool, unsafe.Sizeof(s.box)/wordSize)
+
+	markPointerWords(reflect.TypeFor[T](), 0, s.pointers)
+
+	return s
+}
+
+// Records in pointers which words of a value of type t, at the given
+// offset, hold pointers.
+func markPointerWords(t reflect.Type, offset uintptr, pointers []bool) {
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.UnsafePointer, reflect.Map, reflect.Chan, reflect.Func, reflect.String, reflect.Slice:
+
+		pointers[offset/wordSize] = true
+	case reflect.Interface:
+
+		pointers[offset/wordSize] = true
+		pointers[offset/wordSize+1] = true
+	case reflect.Array:
+
+		if t.Elem().Size() == 0 {
+
+			break
+		}
+
+		for i := 0; i != t.Len(); i++ {
+
+			markPointerWords(t.Elem(), offset+uintptr(i)*t.Elem().Size(), pointers)
+		}
+	case reflect.Struct:
+
+		for i := 0; i != t.NumField(); i++ {
+
+			f := t.Field(i)
+
+			markPointerWords(f.Type, offset+f.Offset, pointers)
+		}
+	}
+}
+
+// Copies the shared value into local, atomically word-by-word.
+func (s *SeqLock[T]) copyOut(local *_seqBox[T]) {
+
+	d := unsafe.Pointer(local)
+	p := unsafe.Pointer(&s.box)
+
+	for i, isPointer := range s.pointers {
+
+		off := uintptr(i) * wordSize
+
+		if isPointer {
+
+			*(*unsafe.Pointer)(unsafe.Add(d, off)) = atomic.LoadPointer((*unsafe.Pointer)(unsafe.Add(p, off)))
+		} else {
+
+			*(*uintptr)(unsafe.Add(d, off)) = atomic.LoadUintptr((*uintptr)(unsafe.Add(p, off)))
+		}
+	}
+}
+
+// Copies local into the shared value, atomically word-by-word.
+func (s *SeqLock[T]) copyIn(local *_seqBox[T]) {
+
+	d := unsafe.Pointer(&s.box)
+	p := unsafe.Pointer(local)
+
+	for i, isPointer := range s.pointers {
+
+		off := uintptr(i) * wordSize
+
+		if isPointer {
+
+			atomic.StorePointer((*unsafe.Pointer)(unsafe.Add(d, off)), *(*unsafe.Pointer)(unsafe.Add(p, off)))
+		} else {
+
+			atomic.StoreUintptr((*uintptr)(unsafe.Add(d, off)), *(*uintptr)(unsafe.Add(p, off)))
+		}
+	}
+}
+
+// Obtains a consistent copy of the value, retrying as necessary.
+func (s *SeqLock[T]) Load() (value T) {
+
+	var local _seqBox[T]
+
+	s.load(&local)
+
+	value = local.value
+
+	return
+}
+
+func (s *SeqLock[T]) load(local *_seqBox[T]) {
+
+	for {
+
+		before := s.seq.Load()
+
+		if before&1 == 0 {
+
+			s.copyOut(local)
+
+			if s.seq.Load() == before {
+
+				return
+			}
+		}
+
+		// a write is, or was, in progress
+		runtime.Gosched()
+	}
+}
+
+// Calls fn with a pointer to a consistent copy of the value. Any changes
+// fn makes to the copy are not reflected in the SeqLock.
+func (s *SeqLock[T]) Read(fn func(*T)) {
+
+	var local _seqBox[T]
+
+	s.load(&local)
+
+	fn(&local.value)
+}
+
+// Obtains the number of writes that have been completed.
+func (s *SeqLock[T]) Version() int64 {
+
+	return s.seq.Load() / 2
+}
+
+// Replaces the value.
+func (s *SeqLock[T]) Store(value T) {
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	local := _seqBox[T]{
+		value: value,
+	}
+
+	s.publish(&local)
+}
+
+// Updates the value by calling fn with a pointer to a copy of the current
+// value, and then publishing the copy. Writers are serialised, so fn sees
+// the result of all preceding writes.
+func (s *SeqLock[T]) Write(fn func(*T)) {
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	// writers are serialised, and readers do not modify s.box, so the
+	// writer may copy it directly
+	local := s.box
+
+	fn(&local.value)
+
+	s.publish(&local)
+}
+
+func (s *SeqLock[T]) publish(local *_seqBox[T]) {
+
+	s.seq.Step()
+
+	s.copyIn(local)
+
+	s.seq.Step()
+}
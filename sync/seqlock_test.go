@@ -0,0 +1,315 @@
+package sync_test
+
+import (
+	. "github.com/synesissoftware/syngo/sync"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"strconv"
+	"sync"
+	"testing"
+)
+
+type seqLockConfig struct {
+	Name     string
+	Limit    int64
+	Weights  [3]float64
+	Tags     []string
+	Extra    any
+	Enabled  bool
+	Checksum int64
+}
+
+func makeSeqLockConfig(i int) seqLockConfig {
+
+	name := "config-" + strconv.Itoa(i)
+	limit := int64(i)
+	weights := [3]float64{float64(i), float64(2 * i), float64(3 * i)}
+
+	return seqLockConfig{
+		Name:     name,
+		Limit:    limit,
+		Weights:  weights,
+		Tags:     []string{name},
+		Extra:    limit,
+		Enabled:  i%2 == 0,
+		Checksum: int64(len(name)) + limit + int64(weights[0]+weights[1]+weights[2]),
+	}
+}
+
+func (c *seqLockConfig) isConsistent() bool {
+
+	if c.Checksum != int64(len(c.Name))+c.Limit+int64(c.Weights[0]+c.Weights[1]+c.Weights[2]) {
+
+		return false
+	}
+
+	if len(c.Tags) != 1 || c.Tags[0] != c.Name {
+
+		return false
+	}
+
+	if extra, ok := c.Extra.(int64); !ok || extra != c.Limit {
+
+		return false
+	}
+
+	return c.Enabled == (c.Limit%2 == 0)
+}
+
+func Test_SeqLock(t *testing.T) {
+
+	t.Run("Load() obtains the initial value", func(t *testing.T) {
+
+		s := NewSeqLock(makeSeqLockConfig(1))
+
+		v := s.Load()
+
+		require.Equal(t, "config-1", v.Name)
+		require.True(t, v.isConsistent())
+		require.Equal(t, int64(0), s.Version())
+	})
+
+	t.Run("Store() and Write() update the value", func(t *testing.T) {
+
+		s := NewSeqLock(makeSeqLockConfig(1))
+
+		s.Store(makeSeqLockConfig(2))
+
+		require.Equal(t, int64(2), s.Load().Limit)
+		require.Equal(t, int64(1), s.Version())
+
+		s.Write(func(c *seqLockConfig) {
+
+			require.Equal(t, int64(2), c.Limit)
+
+			*c = makeSeqLockConfig(3)
+		})
+
+		require.Equal(t, int64(3), s.Load().Limit)
+		require.Equal(t, int64(2), s.Version())
+	})
+
+	t.Run("Read() changes do not affect the value", func(t *testing.T) {
+
+		s := NewSeqLock(makeSeqLockConfig(1))
+
+		s.Read(func(c *seqLockConfig) {
+
+			require.Equal(t, "config-1", c.Name)
+
+			c.Name = "changed"
+		})
+
+		require.Equal(t, "config-1", s.Load().Name)
+	})
+
+	t.Run("values of sizes that are not a whole number of words", func(t *testing.T) {
+
+		type small struct {
+			a, b, c int8
+		}
+
+		s := NewSeqLock(small{1, 2, 3})
+
+		s.Store(small{4, 5, 6})
+
+		require.Equal(t, small{4, 5, 6}, s.Load())
+
+		b := NewSeqLock(true)
+
+		b.Store(false)
+
+		require.False(t, b.Load())
+	})
+
+	t.Run("readers never see a torn value while writers update", func(t *testing.T) {
+
+		s := NewSeqLock(makeSeqLockConfig(0))
+
+		const numReaders = 8
+		const numWriters = 2
+		const numWrites = 2_000
+
+		stop := NewBoolLatch()
+
+		var readers sync.WaitGroup
+		var writers sync.WaitGroup
+
+		for i := 0; i != numReaders; i++ {
+
+			readers.Go(func() {
+
+				for !stop.Load() {
+
+					v := s.Load()
+
+					if !v.isConsistent() {
+
+						assert.Fail(t, "inconsistent value", "%#v", v)
+					}
+
+					s.Read(func(c *seqLockConfig) {
+
+						if !c.isConsistent() {
+
+							assert.Fail(t, "inconsistent value", "%#v", *c)
+						}
+					})
+				}
+			})
+		}
+
+		for i := 0; i != numWriters; i++ {
+
+			writers.Go(func() {
+
+				for j := 0; j != numWrites; j++ {
+
+					if j%2 == 0 {
+
+						s.Store(makeSeqLockConfig(j))
+					} else {
+
+						s.Write(func(c *seqLockConfig) {
+
+							*c = makeSeqLockConfig(int(c.Limit) + 1)
+						})
+					}
+				}
+			})
+		}
+
+		writers.Wait()
+
+		stop.Set()
+
+		readers.Wait()
+
+		require.Equal(t, int64(numWriters*numWrites), s.Version())
+	})
+}
+
+type benchmarkConfig struct {
+	A, B, C, D int64
+	Name       string
+}
+
+func Benchmark_SeqLock_Load(b *testing.B) {
+
+	s := NewSeqLock(benchmarkConfig{Name: "benchmark"})
+
+	b.RunParallel(func(pb *testing.PB) {
+
+		var sum int64
+
+		for pb.Next() {
+
+			v := s.Load()
+
+			sum += v.A
+		}
+
+		_ = sum
+	})
+}
+
+func Benchmark_RWMutex_Load(b *testing.B) {
+
+	var mx sync.RWMutex
+
+	config := benchmarkConfig{Name: "benchmark"}
+
+	b.RunParallel(func(pb *testing.PB) {
+
+		var sum int64
+
+		for pb.Next() {
+
+			mx.RLock()
+			v := config
+			mx.RUnlock()
+
+			sum += v.A
+		}
+
+		_ = sum
+	})
+}
+
+func Benchmark_SeqLock_Load_with_writer(b *testing.B) {
+
+	s := NewSeqLock(benchmarkConfig{Name: "benchmark"})
+
+	stop := NewBoolLatch()
+
+	var wg sync.WaitGroup
+
+	wg.Go(func() {
+
+		for i := int64(0); !stop.Load(); i++ {
+
+			s.Store(benchmarkConfig{A: i, Name: "benchmark"})
+		}
+	})
+
+	b.RunParallel(func(pb *testing.PB) {
+
+		var sum int64
+
+		for pb.Next() {
+
+			v := s.Load()
+
+			sum += v.A
+		}
+
+		_ = sum
+	})
+
+	stop.Set()
+
+	wg.Wait()
+}
+
+func Benchmark_RWMutex_Load_with_writer(b *testing.B) {
+
+	var mx sync.RWMutex
+
+	config := benchmarkConfig{Name: "benchmark"}
+
+	stop := NewBoolLatch()
+
+	var wg sync.WaitGroup
+
+	wg.Go(func() {
+
+		for i := int64(0); !stop.Load(); i++ {
+
+			mx.Lock()
+			config = benchmarkConfig{A: i, Name: "benchmark"}
+			mx.Unlock()
+		}
+	})
+
+	b.RunParallel(func(pb *testing.PB) {
+
+		var sum int64
+
+		for pb.Next() {
+
+			mx.RLock()
+			v := config
+			mx.RUnlock()
+
+			sum += v.A
+		}
+
+		_ = sum
+	})
+
+	stop.Set()
+
+	wg.Wait()
+}
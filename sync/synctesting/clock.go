@@ -0,0 +1,175 @@
+// Copyright 2019-2026 Matthew Wilson and Synesis Information Systems. All
+// rights reserved. Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+ * Created: 19th October 2026
+ * Updated:
+ */
+
+// Definition of a manually-advanced clock for deterministic tests.
+
+package synctesting
+
+import (
+	syngo_sync "github.com/synesissoftware/syngo/sync"
+
+	"sync"
+	"time"
+)
+
+// A Clock whose time changes only when advanced explicitly, for the
+// deterministic testing of the timed types of syngo/sync (WindowCounter,
+// TokenBucket, CircuitBreaker, Progress, etc.).
+//
+// Channels obtained from After() receive the clock's time when it is
+// advanced to (or beyond) their expiry.
+//
+// The zero value is ready for use, and reads the Unix epoch.
+type FakeClock struct {
+	mx     sync.Mutex
+	cond   *sync.Cond
+	nanos  int64
+	timers []_fakeTimer
+}
+
+var _ syngo_sync.Clock = (*FakeClock)(nil)
+
+type _fakeTimer struct {
+	when int64
+	ch   chan time.Time
+}
+
+// Creates a new FakeClock that reads start.
+func NewFakeClock(start time.Time) *FakeClock {
+
+	return &FakeClock{
+		nanos: start.UnixNano(),
+	}
+}
+
+// Obtains the current time of the clock.
+func (c *FakeClock) Now() time.Time {
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	return time.Unix(0, c.nanos)
+}
+
+// Obtains a channel that receives the clock's time once it has been
+// advanced by at least d. If d is not positive, the channel receives
+// immediately.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+
+	ch := make(chan time.Time, 1)
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if d <= 0 {
+
+		ch <- time.Unix(0, c.nanos)
+	} else {
+
+		c.timers = append(c.timers, _fakeTimer{
+			when: c.nanos + int64(d),
+			ch:   ch,
+		})
+
+		c.broadcast()
+	}
+
+	return ch
+}
+
+// Advances the clock by d, firing all timers that expire.
+func (c *FakeClock) Advance(d time.Duration) {
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.nanos += int64(d)
+
+	pending := c.timers[:0]
+
+	for _, timer := range c.timers {
+
+		if timer.when <= c.nanos {
+
+			timer.ch <- time.Unix(0, c.nanos)
+		} else {
+
+			pending = append(pending, timer)
+		}
+	}
+
+	c.timers = pending
+
+	c.broadcast()
+}
+
+// Obtains the number of timers, obtained from After(), that have not yet
+// fired.
+func (c *FakeClock) NumTimers() int {
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	return len(c.timers)
+}
+
+// Blocks the caller until at least n timers are pending, such as when
+// goroutines under test are expected to be waiting on the clock, or until
+// timeout (in real time) elapses.
+//
+// Returns:
+// true if n timers are pending; false if timeout elapsed
+func (c *FakeClock) AwaitTimers(n int, timeout time.Duration) bool {
+
+	expired := false
+
+	stop := time.AfterFunc(timeout, func() {
+
+		c.mx.Lock()
+		expired = true
+		c.broadcast()
+		c.mx.Unlock()
+	})
+
+	defer stop.Stop()
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	for len(c.timers) < n {
+
+		if expired {
+
+			return false
+		}
+
+		c.getCond().Wait()
+	}
+
+	return true
+}
+
+func (c *FakeClock) getCond() *sync.Cond {
+
+	if c.cond == nil {
+
+		c.cond = sync.NewCond(&c.mx)
+	}
+
+	return c.cond
+}
+
+// Wakes any callers of AwaitTimers(). Must be called with mx held.
+func (c *FakeClock) broadcast() {
+
+	if c.cond != nil {
+
+		c.cond.Broadcast()
+	}
+}
@@ -0,0 +1,117 @@
+package synctesting_test
+
+import (
+	. "github.com/synesissoftware/syngo/sync/synctesting"
+
+	syngo_sync "github.com/synesissoftware/syngo/sync"
+
+	"github.com/stretchr/testify/require"
+
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_FakeClock(t *testing.T) {
+
+	t.Run("zero value reads the Unix epoch", func(t *testing.T) {
+
+		var clock FakeClock
+
+		require.Equal(t, time.Unix(0, 0), clock.Now())
+	})
+
+	t.Run("Advance() changes Now()", func(t *testing.T) {
+
+		start := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+		clock := NewFakeClock(start)
+
+		require.True(t, start.Equal(clock.Now()))
+
+		clock.Advance(time.Minute)
+
+		require.True(t, start.Add(time.Minute).Equal(clock.Now()))
+	})
+
+	t.Run("After() fires only when advanced to expiry", func(t *testing.T) {
+
+		clock := NewFakeClock(time.Unix(0, 0))
+
+		ch := clock.After(time.Second)
+
+		require.Equal(t, 1, clock.NumTimers())
+
+		clock.Advance(999 * time.Millisecond)
+
+		select {
+		case <-ch:
+
+			require.Fail(t, "timer fired early")
+		default:
+		}
+
+		clock.Advance(time.Millisecond)
+
+		require.Equal(t, time.Unix(1, 0), <-ch)
+		require.Equal(t, 0, clock.NumTimers())
+	})
+
+	t.Run("After() with non-positive duration fires immediately", func(t *testing.T) {
+
+		var clock FakeClock
+
+		<-clock.After(0)
+
+		require.Equal(t, 0, clock.NumTimers())
+	})
+
+	t.Run("AwaitTimers()", func(t *testing.T) {
+
+		var clock FakeClock
+
+		require.False(t, clock.AwaitTimers(1, time.Millisecond))
+
+		var wg sync.WaitGroup
+
+		for i := 0; i != 3; i++ {
+
+			wg.Go(func() {
+
+				<-clock.After(time.Second)
+			})
+		}
+
+		require.True(t, clock.AwaitTimers(3, time.Second))
+
+		clock.Advance(time.Second)
+
+		wg.Wait()
+	})
+
+	t.Run("drives a TokenBucket deterministically", func(t *testing.T) {
+
+		var clock FakeClock
+
+		bucket := syngo_sync.NewTokenBucket(1, 1, &clock)
+
+		require.True(t, bucket.Allow())
+
+		var wg sync.WaitGroup
+		var err error
+
+		wg.Go(func() {
+
+			err = bucket.Wait(context.Background())
+		})
+
+		require.True(t, clock.AwaitTimers(1, time.Second))
+
+		clock.Advance(time.Second)
+
+		wg.Wait()
+
+		require.Nil(t, err)
+	})
+}
@@ -0,0 +1,251 @@
+// Copyright 2019-2026 Matthew Wilson and Synesis Information Systems. All
+// rights reserved. Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+ * Created: 19th October 2026
+ * Updated:
+ */
+
+// Package synctesting provides helpers for testing code that uses the
+// types of syngo/sync: assertions on latches, a manually-advanced Clock,
+// and checks for goroutines left blocked on waits.
+package synctesting
+
+import (
+	syngo_sync "github.com/synesissoftware/syngo/sync"
+
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// The time for which VerifyNoLeaks() waits for goroutines to exit before
+// reporting a leak.
+var LeakCheckTimeout = time.Second
+
+// Implemented by the counting latches (DownLatch and UpLatch) and by
+// Progress.
+type Stepper interface {
+	syngo_sync.Latch
+	Step() (flipped, isLatched bool, newCount int64)
+}
+
+// Fails the test, and stops its execution, if latch does not latch within
+// d.
+func RequireFlipsWithin(t testing.TB, latch syngo_sync.Latch, d time.Duration) {
+
+	t.Helper()
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-latch.Done():
+	case <-timer.C:
+
+		t.Fatalf("%T did not latch within %v", latch, d)
+	}
+}
+
+// Fails the test, and stops its execution, if latch is latched, or latches
+// within d.
+func RequireNeverFlips(t testing.TB, latch syngo_sync.Latch, d time.Duration) {
+
+	t.Helper()
+
+	if latch.IsLatched() {
+
+		t.Fatalf("%T is already latched", latch)
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-latch.Done():
+
+		t.Fatalf("%T latched within %v", latch, d)
+	case <-timer.C:
+	}
+}
+
+// Steps latch n times, failing the test, and stopping its execution,
+// unless it is the n'th step - and no earlier one - that flips it.
+func RequireStepsExactly(t testing.TB, latch Stepper, n int64) {
+
+	t.Helper()
+
+	if n < 1 {
+
+		t.Fatalf("a latch cannot be flipped in %d steps", n)
+	}
+
+	for i := int64(1); i <= n; i++ {
+
+		flipped, isLatched, newCount := latch.Step()
+
+		if i != n {
+
+			if isLatched {
+
+				t.Fatalf("%T latched after %d step(s) (count %d), rather than %d", latch, i, newCount, n)
+			}
+		} else {
+
+			if !flipped {
+
+				t.Fatalf("%T was not flipped by step %d (count %d, latched %t)", latch, n, newCount, isLatched)
+			}
+		}
+	}
+
+	if !latch.IsLatched() {
+
+		t.Fatalf("%T does not report being latched after being flipped", latch)
+	}
+}
+
+// Records the goroutines that exist, and arranges for the test to fail,
+// when it completes, if any other goroutines - other than the one running
+// the test - have not exited within LeakCheckTimeout. The failure reports
+// the stacks of the leaked goroutines, and how many of them are blocked in
+// waits on syngo/sync types.
+//
+// Since goroutines are identified individually, rather than counted,
+// goroutines that exit, or are started, concurrently by other tests do not
+// affect the check.
+//
+// Should be called at the start of the test, before any goroutines are
+// started.
+func VerifyNoLeaks(t testing.TB) {
+
+	t.Helper()
+
+	existing := make(map[uint64]bool)
+
+	for _, g := range goroutines() {
+
+		existing[g.id] = true
+	}
+
+	t.Cleanup(func() {
+
+		t.Helper()
+
+		// polled, rather than by any helper that would itself start a
+		// goroutine
+		deadline := time.Now().Add(LeakCheckTimeout)
+
+		for {
+
+			var leaked []goroutine
+
+			// the first is that of the caller
+			for _, g := range goroutines()[1:] {
+
+				if !existing[g.id] {
+
+					leaked = append(leaked, g)
+				}
+			}
+
+			if len(leaked) == 0 {
+
+				return
+			}
+
+			if time.Now().After(deadline) {
+
+				var numWaiting int
+				var sb strings.Builder
+
+				for _, g := range leaked {
+
+					if g.isWaiting() {
+
+						numWaiting++
+					}
+
+					sb.WriteString("\n")
+					sb.WriteString(g.stack)
+				}
+
+				t.Errorf("leaked %d goroutine(s), of which %d blocked in waits:\n%s", len(leaked), numWaiting, sb.String())
+
+				return
+			}
+
+			time.Sleep(time.Millisecond)
+		}
+	})
+}
+
+// The function names of syngo/sync begin with this prefix.
+var syngoSyncPrefix = reflect.TypeFor[syngo_sync.BoolLatch]().PkgPath() + "."
+
+// A goroutine, as described by runtime.Stack().
+type goroutine struct {
+	id    uint64
+	stack string
+}
+
+// Obtains all goroutines, beginning with that of the caller.
+func goroutines() (gs []goroutine) {
+
+	buf := make([]byte, 64<<10)
+
+	for {
+
+		n := runtime.Stack(buf, true)
+
+		if n < len(buf) {
+
+			buf = buf[:n]
+
+			break
+		}
+
+		buf = make([]byte, 2*len(buf))
+	}
+
+	for stack := range strings.SplitSeq(string(buf), "\n\n") {
+
+		// e.g. "goroutine 18 [chan receive]:"
+		var id uint64
+
+		if _, err := fmt.Sscanf(stack, "goroutine %d ", &id); err != nil {
+
+			continue
+		}
+
+		gs = append(gs, goroutine{
+			id:    id,
+			stack: stack,
+		})
+	}
+
+	return
+}
+
+// Indicates whether the goroutine is blocked in a wait on a syngo/sync
+// type, i.e. whether the innermost function of its stack that is not part
+// of the runtime is one of syngo/sync.
+func (g goroutine) isWaiting() bool {
+
+	lines := strings.Split(g.stack, "\n")
+
+	// the header is followed by pairs of function and file lines
+	for i := 1; i < len(lines); i += 2 {
+
+		if !strings.HasPrefix(lines[i], "runtime.") {
+
+			return strings.HasPrefix(lines[i], syngoSyncPrefix)
+		}
+	}
+
+	return false
+}
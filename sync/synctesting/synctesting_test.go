@@ -0,0 +1,286 @@
+package synctesting_test
+
+import (
+	. "github.com/synesissoftware/syngo/sync/synctesting"
+
+	syngo_sync "github.com/synesissoftware/syngo/sync"
+
+	"github.com/stretchr/testify/require"
+
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// A testing.TB that records failures, so that the helpers' failures may
+// themselves be tested.
+type fakeT struct {
+	testing.TB
+	failed   bool
+	message  string
+	cleanups []func()
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...any) {
+
+	t.failed = true
+	t.message = fmt.Sprintf(format, args...)
+}
+
+func (t *fakeT) Fatalf(format string, args ...any) {
+
+	t.Errorf(format, args...)
+
+	runtime.Goexit()
+}
+
+func (t *fakeT) Cleanup(fn func()) {
+
+	t.cleanups = append(t.cleanups, fn)
+}
+
+// Runs fn with a fakeT, followed by any cleanup functions that it
+// registers, on their own goroutine (so that Fatalf() may exit it), as
+// does the testing package.
+func runFake(fn func(t *fakeT)) *fakeT {
+
+	t := &fakeT{}
+
+	var wg sync.WaitGroup
+
+	wg.Go(func() {
+
+		defer func() {
+
+			for i := len(t.cleanups) - 1; i >= 0; i-- {
+
+				t.cleanups[i]()
+			}
+		}()
+
+		fn(t)
+	})
+
+	wg.Wait()
+
+	return t
+}
+
+func Test_RequireFlipsWithin(t *testing.T) {
+
+	t.Run("latch that flips", func(t *testing.T) {
+
+		latch := syngo_sync.NewBoolLatch()
+
+		time.AfterFunc(time.Millisecond, func() {
+
+			latch.Set()
+		})
+
+		RequireFlipsWithin(t, &latch, time.Second)
+	})
+
+	t.Run("latch that does not flip", func(t *testing.T) {
+
+		latch := syngo_sync.NewBoolLatch()
+
+		ft := runFake(func(t *fakeT) {
+
+			RequireFlipsWithin(t, &latch, time.Millisecond)
+
+			t.Errorf("not reached")
+		})
+
+		require.True(t, ft.failed)
+		require.Contains(t, ft.message, "did not latch within 1ms")
+	})
+}
+
+func Test_RequireNeverFlips(t *testing.T) {
+
+	t.Run("latch that does not flip", func(t *testing.T) {
+
+		latch := syngo_sync.NewDownLatch(1, 0)
+
+		RequireNeverFlips(t, &latch, 10*time.Millisecond)
+	})
+
+	t.Run("latch that is already latched", func(t *testing.T) {
+
+		latch := syngo_sync.NewBoolLatch()
+
+		latch.Set()
+
+		ft := runFake(func(t *fakeT) {
+
+			RequireNeverFlips(t, &latch, time.Hour)
+		})
+
+		require.True(t, ft.failed)
+		require.Contains(t, ft.message, "already latched")
+	})
+
+	t.Run("latch that flips", func(t *testing.T) {
+
+		latch := syngo_sync.NewBoolLatch()
+
+		time.AfterFunc(time.Millisecond, func() {
+
+			latch.Set()
+		})
+
+		ft := runFake(func(t *fakeT) {
+
+			RequireNeverFlips(t, &latch, time.Second)
+		})
+
+		require.True(t, ft.failed)
+		require.Contains(t, ft.message, "latched within 1s")
+	})
+}
+
+func Test_RequireStepsExactly(t *testing.T) {
+
+	t.Run("DownLatch", func(t *testing.T) {
+
+		latch := syngo_sync.NewDownLatch(10, 3)
+
+		RequireStepsExactly(t, &latch, 7)
+	})
+
+	t.Run("UpLatch", func(t *testing.T) {
+
+		latch := syngo_sync.NewUpLatch(-2, 2)
+
+		RequireStepsExactly(t, &latch, 4)
+	})
+
+	t.Run("Progress", func(t *testing.T) {
+
+		progress := syngo_sync.NewProgress(5, 0, nil)
+
+		RequireStepsExactly(t, progress, 5)
+	})
+
+	t.Run("too few steps", func(t *testing.T) {
+
+		latch := syngo_sync.NewDownLatch(3, 0)
+
+		ft := runFake(func(t *fakeT) {
+
+			RequireStepsExactly(t, &latch, 2)
+		})
+
+		require.True(t, ft.failed)
+		require.Contains(t, ft.message, "was not flipped by step 2")
+	})
+
+	t.Run("too many steps", func(t *testing.T) {
+
+		latch := syngo_sync.NewDownLatch(3, 0)
+
+		ft := runFake(func(t *fakeT) {
+
+			RequireStepsExactly(t, &latch, 4)
+		})
+
+		require.True(t, ft.failed)
+		require.Contains(t, ft.message, "latched after 3 step(s)")
+	})
+}
+
+func Test_VerifyNoLeaks(t *testing.T) {
+
+	t.Run("no leaks", func(t *testing.T) {
+
+		VerifyNoLeaks(t)
+
+		latch := syngo_sync.NewBoolLatch()
+
+		var wg sync.WaitGroup
+
+		wg.Go(func() {
+
+			latch.Wait()
+		})
+
+		latch.Set()
+
+		wg.Wait()
+	})
+
+	t.Run("leaked waiter", func(t *testing.T) {
+
+		defer func(timeout time.Duration) {
+
+			LeakCheckTimeout = timeout
+		}(LeakCheckTimeout)
+
+		LeakCheckTimeout = 10 * time.Millisecond
+
+		latch := syngo_sync.NewBoolLatch()
+
+		ft := runFake(func(t *fakeT) {
+
+			VerifyNoLeaks(t)
+
+			go latch.Wait()
+
+			for latch.NumWaiters() == 0 {
+
+				time.Sleep(time.Millisecond)
+			}
+		})
+
+		latch.Set()
+
+		require.True(t, ft.failed)
+		require.Contains(t, ft.message, "leaked 1 goroutine(s), of which 1 blocked in waits")
+		require.Contains(t, ft.message, "BoolLatch).Wait")
+	})
+
+	t.Run("leaked goroutine not blocked in a wait, while another exits", func(t *testing.T) {
+
+		defer func(timeout time.Duration) {
+
+			LeakCheckTimeout = timeout
+		}(LeakCheckTimeout)
+
+		LeakCheckTimeout = 10 * time.Millisecond
+
+		exit := make(chan struct{})
+		exited := make(chan struct{})
+
+		go func() {
+
+			defer close(exited)
+
+			<-exit
+		}()
+
+		release := make(chan struct{})
+
+		ft := runFake(func(t *fakeT) {
+
+			VerifyNoLeaks(t)
+
+			go func() {
+
+				<-release
+			}()
+
+			// the number of goroutines is unchanged
+			close(exit)
+			<-exited
+		})
+
+		close(release)
+
+		require.True(t, ft.failed)
+		require.Contains(t, ft.message, "leaked 1 goroutine(s), of which 0 blocked in waits")
+	})
+}
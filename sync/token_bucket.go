@@ -0,0 +1,415 @@
+// Copyright 2019-2026 Matthew Wilson and Synesis Information Systems. All
+// rights reserved. Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+ * Created: 19th October 2026
+ * Updated:
+ */
+
+// Definition of a token-bucket rate limiter.
+
+package sync
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// Returned by TokenBucket.WaitN() when the number of tokens requested
+	// exceeds the bucket's burst, or its rate is zero, so that the request
+	// can never be satisfied.
+	ErrTokenBucketExceedsLimits = errors.New("token bucket request exceeds limits")
+	// Returned by TokenBucket.WaitN() when the required delay would exceed
+	// the context's deadline.
+	ErrTokenBucketExceedsDeadline = errors.New("token bucket delay exceeds context deadline")
+
+	errTokenBucketRateMustNotBeNegative  = errors.New("token bucket rate must not be negative")
+	errTokenBucketBurstMustNotBeNegative = errors.New("token bucket burst must not be negative")
+)
+
+type _tokenBucketLimits struct {
+	rate     float64
+	burst    int
+	interval int64 // nanoseconds per token; 0 if rate is infinite
+	tau      int64 // nanoseconds of tolerance, equal to burst * interval
+}
+
+func makeTokenBucketLimits(rate float64, burst int) *_tokenBucketLimits {
+
+	if rate < 0 || math.IsNaN(rate) {
+
+		panic(errTokenBucketRateMustNotBeNegative)
+	}
+
+	if burst < 0 {
+
+		panic(errTokenBucketBurstMustNotBeNegative)
+	}
+
+	limits := &_tokenBucketLimits{
+		rate:  rate,
+		burst: burst,
+	}
+
+	switch {
+	case math.IsInf(rate, 1):
+
+		limits.interval = 0
+	case rate == 0:
+
+		limits.interval = math.MaxInt64
+	default:
+
+		limits.interval = int64(float64(time.Second) / rate)
+
+		if limits.interval < 1 {
+
+			limits.interval = 1
+		}
+	}
+
+	if limits.interval != 0 && int64(burst) > math.MaxInt64/limits.interval {
+
+		limits.tau = math.MaxInt64
+	} else {
+
+		limits.tau = int64(burst) * limits.interval
+	}
+
+	return limits
+}
+
+// A token-bucket rate limiter that may be operated safely by multiple
+// concurrent goroutines, permitting events at a sustained rate with bursts
+// of up to a given size.
+//
+// The bucket is implemented as a generic cell rate algorithm over a single
+// atomic "theoretical arrival time", so that Allow(), AllowN(), and
+// Reserve() are lock-free. The rate and burst may be changed at any time by
+// SetLimits(). A rate of zero permits no events.
+type TokenBucket struct {
+	tat     int64 // theoretical arrival time, in nanoseconds since origin
+	limits  atomic.Pointer[_tokenBucketLimits]
+	clock   Clock
+	origin  time.Time
+	waiting _waiterCount
+}
+
+// Creates a new TokenBucket, initially full, that refills at rate tokens
+// per second (which may be math.Inf(1) for no limit) up to a maximum of
+// burst tokens, and whose times are obtained from clock; if clock is nil,
+// the SystemClock is used.
+//
+// Preconditions:
+// - rate >= 0;
+// - burst >= 0;
+func NewTokenBucket(rate float64, burst int, clock Clock) *TokenBucket {
+
+	clock = clockOrSystem(clock)
+
+	b := &TokenBucket{
+		clock:  clock,
+		origin: clock.Now(),
+	}
+
+	limits := makeTokenBucketLimits(rate, burst)
+
+	b.limits.Store(limits)
+
+	// start full
+	b.tat = -limits.tau
+
+	return b
+}
+
+func (b *TokenBucket) now() int64 {
+
+	return int64(b.clock.Now().Sub(b.origin))
+}
+
+// Obtains the arrival time that would follow taking n tokens at now.
+func nextArrival(tat, now int64, n int, limits *_tokenBucketLimits) int64 {
+
+	if tat < now-limits.tau {
+
+		tat = now - limits.tau
+	}
+
+	if limits.interval != 0 && int64(n) > (math.MaxInt64-limits.tau-now)/limits.interval {
+
+		return math.MaxInt64
+	}
+
+	return tat + int64(n)*limits.interval
+}
+
+// Takes a token if one is available.
+//
+// Returns:
+// true if a token was taken; false otherwise
+func (b *TokenBucket) Allow() bool {
+
+	return b.AllowN(1)
+}
+
+// Takes n tokens if they are all available; otherwise takes none.
+//
+// Returns:
+// true if the tokens were taken; false otherwise
+func (b *TokenBucket) AllowN(n int) bool {
+
+	if n <= 0 {
+
+		return true
+	}
+
+	limits := b.limits.Load()
+
+	switch limits.interval {
+	case 0:
+
+		return true
+	case math.MaxInt64:
+
+		return false
+	}
+
+	now := b.now()
+
+	for {
+
+		tat := atomic.LoadInt64(&b.tat)
+		next := nextArrival(tat, now, n, limits)
+
+		if next > now {
+
+			return false
+		}
+
+		if atomic.CompareAndSwapInt64(&b.tat, tat, next) {
+
+			return true
+		}
+	}
+}
+
+// Takes n tokens, whether or not they are currently available, and
+// obtains the time that the caller must wait before acting upon them.
+//
+// Returns:
+// delay - the time to wait, which is 0 if the tokens were available;
+// ok - false (and no tokens taken) if n exceeds the burst or the rate is
+// zero, meaning that the request can never be satisfied
+func (b *TokenBucket) Reserve(n int) (delay time.Duration, ok bool) {
+
+	if n <= 0 {
+
+		return 0, true
+	}
+
+	limits := b.limits.Load()
+
+	if limits.interval == 0 {
+
+		return 0, true
+	}
+
+	if n > limits.burst || limits.rate == 0 {
+
+		return 0, false
+	}
+
+	now := b.now()
+
+	for {
+
+		tat := atomic.LoadInt64(&b.tat)
+		next := nextArrival(tat, now, n, limits)
+
+		if atomic.CompareAndSwapInt64(&b.tat, tat, next) {
+
+			if next > now {
+
+				delay = time.Duration(next - now)
+			}
+
+			return delay, true
+		}
+	}
+}
+
+// Returns n tokens previously taken by Reserve(), such as when the caller
+// abandons the wait.
+func (b *TokenBucket) cancel(n int) {
+
+	limits := b.limits.Load()
+
+	if limits.interval == 0 || limits.interval == math.MaxInt64 {
+
+		return
+	}
+
+	atomic.AddInt64(&b.tat, -int64(n)*limits.interval)
+}
+
+// Blocks until a token is available and takes it, or ctx is done.
+func (b *TokenBucket) Wait(ctx context.Context) error {
+
+	return b.WaitN(ctx, 1)
+}
+
+// Blocks until n tokens are available and takes them, or ctx is done.
+//
+// Since a context's deadline is in terms of the system clock, the delay
+// until the tokens are available is compared with the time remaining until
+// the deadline, rather than the bucket's clock being compared with the
+// deadline itself.
+//
+// Returns:
+// nil if the tokens were taken; ErrTokenBucketExceedsLimits if they never
+// can be; ErrTokenBucketExceedsDeadline if they would not be available by
+// ctx's deadline; ctx.Err() if ctx is done while waiting
+func (b *TokenBucket) WaitN(ctx context.Context, n int) error {
+
+	if err := ctx.Err(); err != nil {
+
+		return err
+	}
+
+	delay, ok := b.Reserve(n)
+
+	if !ok {
+
+		return ErrTokenBucketExceedsLimits
+	}
+
+	if delay == 0 {
+
+		return nil
+	}
+
+	if deadline, hasDeadline := ctx.Deadline(); hasDeadline && delay > time.Until(deadline) {
+
+		b.cancel(n)
+
+		return ErrTokenBucketExceedsDeadline
+	}
+
+	token := b.waiting.begin()
+	defer b.waiting.end(token)
+
+	select {
+	case <-b.clock.After(delay):
+
+		return nil
+	case <-ctx.Done():
+
+		b.cancel(n)
+
+		return ctx.Err()
+	}
+}
+
+// Obtains the number of goroutines currently blocked in Wait() or WaitN().
+func (b *TokenBucket) NumWaiters() int {
+
+	return int(b.waiting.load())
+}
+
+// Obtains the number of tokens currently available, which may be
+// fractional.
+func (b *TokenBucket) Tokens() float64 {
+
+	limits := b.limits.Load()
+
+	switch limits.interval {
+	case 0:
+
+		return math.Inf(1)
+	case math.MaxInt64:
+
+		return 0
+	}
+
+	now := b.now()
+	tat := atomic.LoadInt64(&b.tat)
+
+	available := float64(now-tat) / float64(limits.interval)
+
+	switch {
+	case available < 0:
+
+		return 0
+	case available > float64(limits.burst):
+
+		return float64(limits.burst)
+	default:
+
+		return available
+	}
+}
+
+// Obtains the current rate and burst.
+func (b *TokenBucket) Limits() (rate float64, burst int) {
+
+	limits := b.limits.Load()
+
+	return limits.rate, limits.burst
+}
+
+// Changes the rate and burst of the bucket, preserving the number of
+// tokens currently available (up to the new burst).
+//
+// Preconditions:
+// - rate >= 0;
+// - burst >= 0;
+func (b *TokenBucket) SetLimits(rate float64, burst int) {
+
+	newLimits := makeTokenBucketLimits(rate, burst)
+
+	oldLimits := b.limits.Swap(newLimits)
+
+	now := b.now()
+
+	for {
+
+		tat := atomic.LoadInt64(&b.tat)
+
+		var available float64
+
+		switch oldLimits.interval {
+		case 0:
+
+			available = math.Inf(1)
+		case math.MaxInt64:
+
+			available = 0
+		default:
+
+			available = math.Max(0, float64(now-tat)/float64(oldLimits.interval))
+		}
+
+		available = math.Min(available, float64(newLimits.burst))
+
+		var next int64
+
+		switch newLimits.interval {
+		case 0, math.MaxInt64:
+
+			next = now
+		default:
+
+			next = now - int64(available*float64(newLimits.interval))
+		}
+
+		if atomic.CompareAndSwapInt64(&b.tat, tat, next) {
+
+			return
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package sync_test
+
+import (
+	. "github.com/synesissoftware/syngo/sync"
+
+	"github.com/synesissoftware/syngo/sync/synctesting"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"context"
+	"math"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_TokenBucket(t *testing.T) {
+
+	t.Run("NewTokenBucket() panics on invalid arguments", func(t *testing.T) {
+
+		require.Panics(t, func() {
+
+			NewTokenBucket(-1, 1, nil)
+		})
+
+		require.Panics(t, func() {
+
+			NewTokenBucket(1, -1, nil)
+		})
+	})
+
+	t.Run("Allow() permits a burst and then the sustained rate", func(t *testing.T) {
+
+		clock := &synctesting.FakeClock{}
+
+		bucket := NewTokenBucket(10, 5, clock)
+
+		require.Equal(t, 5.0, bucket.Tokens())
+
+		for i := 0; i != 5; i++ {
+
+			require.True(t, bucket.Allow(), "i=%d", i)
+		}
+
+		require.False(t, bucket.Allow())
+		require.Equal(t, 0.0, bucket.Tokens())
+
+		clock.Advance(100 * time.Millisecond)
+
+		require.True(t, bucket.Allow())
+		require.False(t, bucket.Allow())
+
+		clock.Advance(time.Hour)
+
+		require.Equal(t, 5.0, bucket.Tokens())
+	})
+
+	t.Run("AllowN() takes all or nothing", func(t *testing.T) {
+
+		clock := &synctesting.FakeClock{}
+
+		bucket := NewTokenBucket(1, 3, clock)
+
+		require.True(t, bucket.AllowN(2))
+		require.False(t, bucket.AllowN(2))
+		require.True(t, bucket.AllowN(1))
+		require.False(t, bucket.AllowN(4))
+		require.True(t, bucket.AllowN(0))
+	})
+
+	t.Run("Reserve() reports the delay", func(t *testing.T) {
+
+		clock := &synctesting.FakeClock{}
+
+		bucket := NewTokenBucket(10, 2, clock)
+
+		delay, ok := bucket.Reserve(2)
+
+		require.True(t, ok)
+		require.Equal(t, time.Duration(0), delay)
+
+		delay, ok = bucket.Reserve(1)
+
+		require.True(t, ok)
+		require.Equal(t, 100*time.Millisecond, delay)
+
+		delay, ok = bucket.Reserve(2)
+
+		require.True(t, ok)
+		require.Equal(t, 300*time.Millisecond, delay)
+
+		_, ok = bucket.Reserve(3)
+
+		require.False(t, ok)
+	})
+
+	t.Run("infinite and zero rates", func(t *testing.T) {
+
+		unlimited := NewTokenBucket(math.Inf(1), 0, nil)
+
+		for i := 0; i != 1_000; i++ {
+
+			require.True(t, unlimited.Allow())
+		}
+
+		require.Nil(t, unlimited.WaitN(context.Background(), 1_000_000))
+
+		blocked := NewTokenBucket(0, 10, nil)
+
+		require.False(t, blocked.Allow())
+
+		_, ok := blocked.Reserve(1)
+
+		require.False(t, ok)
+		require.ErrorIs(t, blocked.Wait(context.Background()), ErrTokenBucketExceedsLimits)
+	})
+
+	t.Run("Wait() blocks until the clock advances", func(t *testing.T) {
+
+		clock := &synctesting.FakeClock{}
+
+		bucket := NewTokenBucket(1, 1, clock)
+
+		require.Nil(t, bucket.Wait(context.Background()))
+
+		var done atomic.Bool
+		var wg sync.WaitGroup
+
+		wg.Go(func() {
+
+			assert.Nil(t, bucket.Wait(context.Background()))
+
+			done.Store(true)
+		})
+
+		require.True(t, clock.AwaitTimers(1, time.Second))
+
+		require.False(t, done.Load())
+
+		clock.Advance(time.Second)
+
+		wg.Wait()
+
+		require.True(t, done.Load())
+	})
+
+	t.Run("Wait() returns the tokens when cancelled", func(t *testing.T) {
+
+		clock := &synctesting.FakeClock{}
+
+		bucket := NewTokenBucket(1, 1, clock)
+
+		require.True(t, bucket.Allow())
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		var wg sync.WaitGroup
+
+		wg.Go(func() {
+
+			assert.ErrorIs(t, bucket.Wait(ctx), context.Canceled)
+		})
+
+		require.True(t, clock.AwaitTimers(1, time.Second))
+
+		cancel()
+
+		wg.Wait()
+
+		clock.Advance(time.Second)
+
+		require.True(t, bucket.Allow())
+	})
+
+	t.Run("Wait() compares the delay with the deadline independently of the clock", func(t *testing.T) {
+
+		clock := synctesting.NewFakeClock(time.Now().Add(24 * time.Hour))
+
+		bucket := NewTokenBucket(1, 1, clock)
+
+		require.True(t, bucket.Allow())
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		var wg sync.WaitGroup
+
+		wg.Go(func() {
+
+			assert.Nil(t, bucket.Wait(ctx))
+		})
+
+		require.True(t, clock.AwaitTimers(1, time.Second))
+
+		clock.Advance(time.Second)
+
+		wg.Wait()
+	})
+
+	t.Run("Wait() fails fast when the delay exceeds the deadline", func(t *testing.T) {
+
+		bucket := NewTokenBucket(0.001, 1, nil)
+
+		require.True(t, bucket.Allow())
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		require.ErrorIs(t, bucket.Wait(ctx), ErrTokenBucketExceedsDeadline)
+	})
+
+	t.Run("SetLimits() changes the rate and burst", func(t *testing.T) {
+
+		clock := &synctesting.FakeClock{}
+
+		bucket := NewTokenBucket(1, 10, clock)
+
+		require.True(t, bucket.AllowN(6))
+
+		bucket.SetLimits(100, 2)
+
+		rate, burst := bucket.Limits()
+
+		require.Equal(t, 100.0, rate)
+		require.Equal(t, 2, burst)
+		require.InDelta(t, 2.0, bucket.Tokens(), 1e-9)
+
+		require.True(t, bucket.AllowN(2))
+		require.False(t, bucket.Allow())
+
+		clock.Advance(10 * time.Millisecond)
+
+		require.True(t, bucket.Allow())
+	})
+
+	t.Run("Allow() from many goroutines never exceeds the burst", func(t *testing.T) {
+
+		clock := &synctesting.FakeClock{}
+
+		bucket := NewTokenBucket(1, 100, clock)
+
+		var allowed atomic.Int64
+		var wg sync.WaitGroup
+
+		for i := 0; i != 10; i++ {
+
+			wg.Go(func() {
+
+				for j := 0; j != 1_000; j++ {
+
+					if bucket.Allow() {
+
+						allowed.Add(1)
+					}
+				}
+			})
+		}
+
+		wg.Wait()
+
+		require.Equal(t, int64(100), allowed.Load())
+	})
+}
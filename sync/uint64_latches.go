@@ -0,0 +1,342 @@
+// Copyright 2019-2026 Matthew Wilson and Synesis Information Systems. All
+// rights reserved. Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+ * Created: 19th October 2026
+ * Updated:
+ */
+
+// Definition of numeric latches over the full range of uint64.
+
+package sync
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Count-down-to-zero numeric latch over the full range of uint64.
+//
+// Since every value of the remaining distance is a legitimate state, there
+// is no room for the floor used by _baseLatch; instead, the distance is
+// decremented by compare-and-swap, and never below zero.
+type _baseUint64Latch struct {
+	_latchNotifier
+	_observed
+	remaining uint64
+}
+
+func (l *_baseUint64Latch) stepN(n uint64) (flipped, isLatched bool, remaining uint64) {
+
+	for {
+
+		remaining = atomic.LoadUint64(&l.remaining)
+
+		if remaining == 0 {
+
+			isLatched = true
+
+			return
+		}
+
+		if n == 0 {
+
+			return
+		}
+
+		next := remaining - min(n, remaining)
+
+		if atomic.CompareAndSwapUint64(&l.remaining, remaining, next) {
+
+			remaining = next
+
+			if remaining == 0 {
+
+				flipped = true
+				isLatched = true
+
+				l._latchNotifier.fire()
+			}
+
+			return
+		}
+	}
+}
+
+func (l *_baseUint64Latch) load() (isLatched bool, remaining uint64) {
+
+	remaining = atomic.LoadUint64(&l.remaining)
+
+	isLatched = remaining == 0
+
+	return
+}
+
+// A unidirectional latch that counts down from an initial value to a lower
+// threshold that may be operated safely by multiple concurrent goroutines.
+//
+// Unlike DownLatch, any initial value and threshold in the range of uint64
+// may be used, so the distance between them may be up to math.MaxUint64.
+// Steps are made by compare-and-swap rather than by atomic addition, so
+// are somewhat more expensive under contention.
+type Uint64DownLatch struct {
+	_baseUint64Latch
+	threshold uint64
+}
+
+// Creates a new Uint64DownLatch.
+//
+// Preconditions:
+// - initialValue > threshold;
+func NewUint64DownLatch(initialValue, threshold uint64) Uint64DownLatch {
+
+	if initialValue <= threshold {
+
+		panic(errDownLatchInitialValueMustBeGreaterThanThreshold)
+	}
+
+	observeCreated("Uint64DownLatch", int64(initialValue))
+
+	return Uint64DownLatch{
+		_baseUint64Latch: _baseUint64Latch{
+			remaining: initialValue - threshold,
+		},
+		threshold: threshold,
+	}
+}
+
+// Steps the latch down by one; no effect if already latched.
+//
+// Returns:
+// flipped - true if this step latched the latch;
+// isLatched - true if the latch is latched;
+// newCount - the count after the step, which is the threshold once latched;
+func (l *Uint64DownLatch) Step() (flipped, isLatched bool, newCount uint64) {
+
+	return l.StepN(1)
+}
+
+// Steps the latch down by n, or by as much of n as remains before the
+// threshold; no effect if already latched.
+//
+// Returns:
+// flipped - true if this step latched the latch;
+// isLatched - true if the latch is latched;
+// newCount - the count after the step, which is the threshold once latched;
+func (l *Uint64DownLatch) StepN(n uint64) (flipped, isLatched bool, newCount uint64) {
+
+	var remaining uint64
+
+	flipped, isLatched, remaining = l._baseUint64Latch.stepN(n)
+
+	newCount = l.threshold + remaining
+
+	if o := l._observed.load(); o != nil {
+
+		observeStep(o, "Uint64DownLatch", l, flipped, isLatched, int64(newCount))
+	}
+
+	return
+}
+
+// Obtains the current value of the latch, without changing its state.
+func (l *Uint64DownLatch) Load() (isLatched bool, count uint64) {
+
+	var remaining uint64
+
+	isLatched, remaining = l._baseUint64Latch.load()
+
+	count = l.threshold + remaining
+
+	return
+}
+
+// Indicates whether the latch has latched, without changing its state.
+func (l *Uint64DownLatch) IsLatched() (isLatched bool) {
+
+	isLatched, _ = l._baseUint64Latch.load()
+
+	return
+}
+
+// Obtains a channel that is closed when the latch latches.
+func (l *Uint64DownLatch) Done() <-chan struct{} {
+
+	return l._latchNotifier.done()
+}
+
+// Blocks the caller until the latch latches.
+func (l *Uint64DownLatch) Wait() {
+
+	if o := l._observed.load(); o != nil {
+
+		observeWait(o, "Uint64DownLatch", l, &l._latchNotifier, nil)
+
+		return
+	}
+
+	l._latchNotifier.wait()
+}
+
+// Blocks the caller until the latch latches or ctx is done.
+//
+// Returns:
+// nil if the latch latched; ctx.Err() otherwise
+func (l *Uint64DownLatch) WaitContext(ctx context.Context) error {
+
+	if o := l._observed.load(); o != nil {
+
+		return observeWait(o, "Uint64DownLatch", l, &l._latchNotifier, ctx)
+	}
+
+	return l._latchNotifier.waitContext(ctx)
+}
+
+// Installs o as the observer of the instance, in place of any global
+// observer. Passing nil reverts to the global observer.
+func (l *Uint64DownLatch) SetObserver(o Observer) {
+
+	l._observed.set(o)
+}
+
+// Obtains the number of goroutines currently blocked in Wait() or
+// WaitContext().
+func (l *Uint64DownLatch) NumWaiters() int {
+
+	return int(l._latchNotifier.numWaiters())
+}
+
+// A unidirectional latch that counts up from an initial value to a higher
+// threshold that may be operated safely by multiple concurrent goroutines.
+//
+// Unlike UpLatch, any initial value and threshold in the range of uint64
+// may be used, so the distance between them may be up to math.MaxUint64.
+// Steps are made by compare-and-swap rather than by atomic addition, so
+// are somewhat more expensive under contention.
+type Uint64UpLatch struct {
+	_baseUint64Latch
+	threshold uint64
+}
+
+// Creates a new Uint64UpLatch.
+//
+// Preconditions:
+// - initialValue < threshold;
+func NewUint64UpLatch(initialValue, threshold uint64) Uint64UpLatch {
+
+	if initialValue >= threshold {
+
+		panic(errUpLatchInitialValueMustBeLessThanThreshold)
+	}
+
+	observeCreated("Uint64UpLatch", int64(initialValue))
+
+	return Uint64UpLatch{
+		_baseUint64Latch: _baseUint64Latch{
+			remaining: threshold - initialValue,
+		},
+		threshold: threshold,
+	}
+}
+
+// Steps the latch up by one; no effect if already latched.
+//
+// Returns:
+// flipped - true if this step latched the latch;
+// isLatched - true if the latch is latched;
+// newCount - the count after the step, which is the threshold once latched;
+func (l *Uint64UpLatch) Step() (flipped, isLatched bool, newCount uint64) {
+
+	return l.StepN(1)
+}
+
+// Steps the latch up by n, or by as much of n as remains before the
+// threshold; no effect if already latched.
+//
+// Returns:
+// flipped - true if this step latched the latch;
+// isLatched - true if the latch is latched;
+// newCount - the count after the step, which is the threshold once latched;
+func (l *Uint64UpLatch) StepN(n uint64) (flipped, isLatched bool, newCount uint64) {
+
+	var remaining uint64
+
+	flipped, isLatched, remaining = l._baseUint64Latch.stepN(n)
+
+	newCount = l.threshold - remaining
+
+	if o := l._observed.load(); o != nil {
+
+		observeStep(o, "Uint64UpLatch", l, flipped, isLatched, int64(newCount))
+	}
+
+	return
+}
+
+// Obtains the current value of the latch, without changing its state.
+func (l *Uint64UpLatch) Load() (isLatched bool, count uint64) {
+
+	var remaining uint64
+
+	isLatched, remaining = l._baseUint64Latch.load()
+
+	count = l.threshold - remaining
+
+	return
+}
+
+// Indicates whether the latch has latched, without changing its state.
+func (l *Uint64UpLatch) IsLatched() (isLatched bool) {
+
+	isLatched, _ = l._baseUint64Latch.load()
+
+	return
+}
+
+// Obtains a channel that is closed when the latch latches.
+func (l *Uint64UpLatch) Done() <-chan struct{} {
+
+	return l._latchNotifier.done()
+}
+
+// Blocks the caller until the latch latches.
+func (l *Uint64UpLatch) Wait() {
+
+	if o := l._observed.load(); o != nil {
+
+		observeWait(o, "Uint64UpLatch", l, &l._latchNotifier, nil)
+
+		return
+	}
+
+	l._latchNotifier.wait()
+}
+
+// Blocks the caller until the latch latches or ctx is done.
+//
+// Returns:
+// nil if the latch latched; ctx.Err() otherwise
+func (l *Uint64UpLatch) WaitContext(ctx context.Context) error {
+
+	if o := l._observed.load(); o != nil {
+
+		return observeWait(o, "Uint64UpLatch", l, &l._latchNotifier, ctx)
+	}
+
+	return l._latchNotifier.waitContext(ctx)
+}
+
+// Installs o as the observer of the instance, in place of any global
+// observer. Passing nil reverts to the global observer.
+func (l *Uint64UpLatch) SetObserver(o Observer) {
+
+	l._observed.set(o)
+}
+
+// Obtains the number of goroutines currently blocked in Wait() or
+// WaitContext().
+func (l *Uint64UpLatch) NumWaiters() int {
+
+	return int(l._latchNotifier.numWaiters())
+}
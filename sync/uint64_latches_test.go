@@ -0,0 +1,363 @@
+package sync_test
+
+import (
+	. "github.com/synesissoftware/syngo/sync"
+
+	"github.com/synesissoftware/syngo/sync/synctesting"
+
+	"github.com/stretchr/testify/require"
+
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Boundary values of the uint64 domain.
+var uint64Boundaries = []uint64{
+	0,
+	1,
+	2,
+	math.MaxInt64 - 1,
+	math.MaxInt64,
+	math.MaxInt64 + 1,
+	math.MaxUint64 - 2,
+	math.MaxUint64 - 1,
+	math.MaxUint64,
+}
+
+func Test_Uint64DownLatch(t *testing.T) {
+
+	t.Run("NewUint64DownLatch() panics unless initial value is greater than threshold", func(t *testing.T) {
+
+		for _, initial := range uint64Boundaries {
+
+			for _, threshold := range uint64Boundaries {
+
+				if initial > threshold {
+
+					require.NotPanics(t, func() {
+
+						_ = NewUint64DownLatch(initial, threshold)
+					})
+				} else {
+
+					require.Panics(t, func() {
+
+						_ = NewUint64DownLatch(initial, threshold)
+					})
+				}
+			}
+		}
+	})
+
+	t.Run("Load() for all boundary ranges", func(t *testing.T) {
+
+		for _, initial := range uint64Boundaries {
+
+			for _, threshold := range uint64Boundaries {
+
+				if initial <= threshold {
+
+					continue
+				}
+
+				latch := NewUint64DownLatch(initial, threshold)
+
+				isLatched, count := latch.Load()
+
+				require.False(t, isLatched)
+				require.Equal(t, initial, count)
+			}
+		}
+	})
+
+	t.Run("the last step of every boundary range flips exactly once", func(t *testing.T) {
+
+		for _, initial := range uint64Boundaries {
+
+			for _, threshold := range uint64Boundaries {
+
+				if initial <= threshold {
+
+					continue
+				}
+
+				t.Run(fmt.Sprintf("[%d, %d)", initial, threshold), func(t *testing.T) {
+
+					latch := NewUint64DownLatch(initial, threshold)
+
+					distance := initial - threshold
+
+					flipped, isLatched, count := latch.StepN(distance - 1)
+
+					require.False(t, flipped)
+					require.False(t, isLatched)
+					require.Equal(t, threshold+1, count)
+
+					flipped, isLatched, count = latch.Step()
+
+					require.True(t, flipped)
+					require.True(t, isLatched)
+					require.Equal(t, threshold, count)
+
+					flipped, isLatched, count = latch.Step()
+
+					require.False(t, flipped)
+					require.True(t, isLatched)
+					require.Equal(t, threshold, count)
+
+					flipped, isLatched, count = latch.StepN(math.MaxUint64)
+
+					require.False(t, flipped)
+					require.True(t, isLatched)
+					require.Equal(t, threshold, count)
+
+					require.True(t, latch.IsLatched())
+				})
+			}
+		}
+	})
+
+	t.Run("full range [MaxUint64, 0) with an overshooting step", func(t *testing.T) {
+
+		latch := NewUint64DownLatch(math.MaxUint64, 0)
+
+		flipped, isLatched, count := latch.StepN(math.MaxUint64 / 2)
+
+		require.False(t, flipped)
+		require.False(t, isLatched)
+		require.Equal(t, uint64(math.MaxUint64-math.MaxUint64/2), count)
+
+		flipped, isLatched, count = latch.StepN(math.MaxUint64)
+
+		require.True(t, flipped)
+		require.True(t, isLatched)
+		require.Equal(t, uint64(0), count)
+	})
+
+	t.Run("StepN(0) does not change the state", func(t *testing.T) {
+
+		latch := NewUint64DownLatch(1, 0)
+
+		flipped, isLatched, count := latch.StepN(0)
+
+		require.False(t, flipped)
+		require.False(t, isLatched)
+		require.Equal(t, uint64(1), count)
+	})
+
+	t.Run("Wait() returns once flipped", func(t *testing.T) {
+
+		latch := NewUint64DownLatch(math.MaxUint64, math.MaxUint64-2)
+
+		var wg sync.WaitGroup
+
+		wg.Go(func() {
+
+			latch.Wait()
+		})
+
+		synctesting.RequireNeverFlips(t, &latch, time.Millisecond)
+
+		latch.Step()
+		latch.Step()
+
+		synctesting.RequireFlipsWithin(t, &latch, time.Second)
+
+		wg.Wait()
+
+		require.Nil(t, latch.WaitContext(context.Background()))
+		require.Equal(t, 0, latch.NumWaiters())
+	})
+
+	t.Run("per-instance observer", func(t *testing.T) {
+
+		var observer recordingObserver
+
+		latch := NewUint64DownLatch(math.MaxUint64, math.MaxUint64-2)
+
+		latch.SetObserver(&observer)
+
+		latch.Step()
+		latch.StepN(5)
+		latch.Step()
+		latch.Wait()
+
+		require.Equal(t, []LatchEventKind{LatchStepped, LatchFlipped, LatchSteppedAfterLatched, LatchWaited}, observer.kinds())
+		require.Equal(t, "Uint64DownLatch", observer.events[0].Type)
+		require.Same(t, &latch, observer.events[0].Latch)
+		require.Equal(t, int64(-2), observer.events[0].Count)
+	})
+}
+
+func Test_Uint64UpLatch(t *testing.T) {
+
+	t.Run("NewUint64UpLatch() panics unless initial value is less than threshold", func(t *testing.T) {
+
+		for _, initial := range uint64Boundaries {
+
+			for _, threshold := range uint64Boundaries {
+
+				if initial < threshold {
+
+					require.NotPanics(t, func() {
+
+						_ = NewUint64UpLatch(initial, threshold)
+					})
+				} else {
+
+					require.Panics(t, func() {
+
+						_ = NewUint64UpLatch(initial, threshold)
+					})
+				}
+			}
+		}
+	})
+
+	t.Run("the last step of every boundary range flips exactly once", func(t *testing.T) {
+
+		for _, initial := range uint64Boundaries {
+
+			for _, threshold := range uint64Boundaries {
+
+				if initial >= threshold {
+
+					continue
+				}
+
+				t.Run(fmt.Sprintf("[%d, %d)", initial, threshold), func(t *testing.T) {
+
+					latch := NewUint64UpLatch(initial, threshold)
+
+					isLatched, count := latch.Load()
+
+					require.False(t, isLatched)
+					require.Equal(t, initial, count)
+
+					distance := threshold - initial
+
+					flipped, isLatched, count := latch.StepN(distance - 1)
+
+					require.False(t, flipped)
+					require.False(t, isLatched)
+					require.Equal(t, threshold-1, count)
+
+					flipped, isLatched, count = latch.Step()
+
+					require.True(t, flipped)
+					require.True(t, isLatched)
+					require.Equal(t, threshold, count)
+
+					flipped, isLatched, count = latch.StepN(math.MaxUint64)
+
+					require.False(t, flipped)
+					require.True(t, isLatched)
+					require.Equal(t, threshold, count)
+				})
+			}
+		}
+	})
+
+	t.Run("full byte-offset range [0, MaxUint64)", func(t *testing.T) {
+
+		latch := NewUint64UpLatch(0, math.MaxUint64)
+
+		var offset uint64
+
+		for _, n := range []uint64{1 << 20, 1 << 40, 1 << 62, 1 << 63} {
+
+			flipped, isLatched, count := latch.StepN(n)
+
+			offset += n
+
+			require.False(t, flipped)
+			require.False(t, isLatched)
+			require.Equal(t, offset, count)
+		}
+
+		flipped, isLatched, count := latch.StepN(math.MaxUint64 - offset)
+
+		require.True(t, flipped)
+		require.True(t, isLatched)
+		require.Equal(t, uint64(math.MaxUint64), count)
+	})
+
+	t.Run("concurrent steps flip exactly once", func(t *testing.T) {
+
+		const numGoroutines = 8
+		const numSteps = 10_000
+
+		latch := NewUint64UpLatch(math.MaxUint64-numGoroutines*numSteps/2, math.MaxUint64)
+
+		var numFlips atomic.Int64
+		var wg sync.WaitGroup
+
+		for i := 0; i != numGoroutines; i++ {
+
+			wg.Go(func() {
+
+				for j := 0; j != numSteps; j++ {
+
+					if flipped, _, _ := latch.Step(); flipped {
+
+						numFlips.Add(1)
+					}
+				}
+			})
+		}
+
+		wg.Wait()
+
+		require.Equal(t, int64(1), numFlips.Load())
+
+		isLatched, count := latch.Load()
+
+		require.True(t, isLatched)
+		require.Equal(t, uint64(math.MaxUint64), count)
+	})
+
+	t.Run("composes with AllOf()", func(t *testing.T) {
+
+		up := NewUint64UpLatch(0, 1)
+		down := NewUint64DownLatch(1, 0)
+
+		composite := AllOf(&up, &down)
+
+		up.Step()
+
+		require.False(t, composite.IsLatched())
+
+		down.Step()
+
+		require.True(t, composite.IsLatched())
+	})
+
+	t.Run("global observer", func(t *testing.T) {
+
+		var global recordingObserver
+
+		previous := SetObserver(&global)
+
+		defer SetObserver(previous)
+
+		latch := NewUint64UpLatch(1, 3)
+
+		latch.StepN(2)
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		cancel()
+
+		require.Nil(t, latch.WaitContext(ctx))
+
+		require.Equal(t, []LatchEventKind{LatchCreated, LatchFlipped, LatchWaited}, global.kinds())
+		require.Equal(t, "Uint64UpLatch", global.events[0].Type)
+		require.Equal(t, int64(1), global.events[0].Count)
+		require.Equal(t, int64(3), global.events[1].Count)
+	})
+}
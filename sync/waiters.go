@@ -0,0 +1,67 @@
+// Copyright 2019-2026 Matthew Wilson and Synesis Information Systems. All
+// rights reserved. Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+ * Created: 19th October 2026
+ * Updated:
+ */
+
+// Introspection of goroutines blocked in waits on the types of this
+// package.
+
+package sync
+
+import (
+	"runtime/pprof"
+	"sync/atomic"
+)
+
+// The name of the runtime/pprof profile that records the stacks of all
+// goroutines currently blocked in a wait on any type of this package, as
+// may be obtained from pprof.Lookup(), or from the /debug/pprof/ endpoint
+// of net/http/pprof, e.g.:
+//
+//	go tool pprof http://localhost:6060/debug/pprof/syngo.latch.waiters
+const WaitersProfileName = "syngo.latch.waiters"
+
+var waitersProfile = pprof.NewProfile(WaitersProfileName)
+
+// Identifies a single wait in the waiters profile. It is not zero-sized,
+// so that each allocation is distinct.
+type _waitToken struct {
+	_ byte
+}
+
+// The number of goroutines blocked in waits on an instance, each of which
+// is also recorded in the waiters profile.
+//
+// Waits that are satisfied without blocking are not recorded.
+type _waiterCount struct {
+	n int64
+}
+
+// Records the start of a blocking wait. The stack recorded in the profile
+// begins at the caller.
+func (c *_waiterCount) begin() (token *_waitToken) {
+
+	atomic.AddInt64(&c.n, 1)
+
+	token = new(_waitToken)
+
+	waitersProfile.Add(token, 1)
+
+	return
+}
+
+func (c *_waiterCount) end(token *_waitToken) {
+
+	waitersProfile.Remove(token)
+
+	atomic.AddInt64(&c.n, -1)
+}
+
+func (c *_waiterCount) load() int64 {
+
+	return atomic.LoadInt64(&c.n)
+}
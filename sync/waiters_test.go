@@ -0,0 +1,226 @@
+package sync_test
+
+import (
+	. "github.com/synesissoftware/syngo/sync"
+
+	"github.com/stretchr/testify/require"
+
+	"bytes"
+	"context"
+	"runtime/pprof"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Waits until cond holds, or fails the test after a second.
+func requireEventually(t *testing.T, cond func() bool) {
+
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+
+	for !cond() {
+
+		if time.Now().After(deadline) {
+
+			require.Fail(t, "condition not satisfied within 1s")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func Test_Waiters(t *testing.T) {
+
+	t.Run("the waiters profile is registered", func(t *testing.T) {
+
+		require.NotNil(t, pprof.Lookup(WaitersProfileName))
+	})
+
+	t.Run("BoolLatch NumWaiters() and profile", func(t *testing.T) {
+
+		profile := pprof.Lookup(WaitersProfileName)
+		before := profile.Count()
+
+		latch := NewBoolLatch()
+
+		require.Equal(t, 0, latch.NumWaiters())
+
+		var wg sync.WaitGroup
+
+		for i := 0; i != 3; i++ {
+
+			wg.Go(func() {
+
+				latch.Wait()
+			})
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		wg.Go(func() {
+
+			_ = latch.WaitContext(ctx)
+		})
+
+		requireEventually(t, func() bool {
+
+			return latch.NumWaiters() == 4
+		})
+
+		require.Equal(t, before+4, profile.Count())
+
+		var buf bytes.Buffer
+
+		require.Nil(t, profile.WriteTo(&buf, 1))
+		require.Contains(t, buf.String(), "BoolLatch).Wait")
+
+		cancel()
+
+		requireEventually(t, func() bool {
+
+			return latch.NumWaiters() == 3
+		})
+
+		latch.Set()
+
+		wg.Wait()
+
+		require.Equal(t, 0, latch.NumWaiters())
+		require.Equal(t, before, profile.Count())
+	})
+
+	t.Run("waits satisfied without blocking are not recorded", func(t *testing.T) {
+
+		profile := pprof.Lookup(WaitersProfileName)
+		before := profile.Count()
+
+		latch := NewDownLatch(1, 0)
+
+		latch.Step()
+		latch.Wait()
+
+		require.Equal(t, 0, latch.NumWaiters())
+		require.Equal(t, before, profile.Count())
+	})
+
+	t.Run("UpCounter NumWaiters()", func(t *testing.T) {
+
+		counter := NewUpCounter(0)
+
+		var wg sync.WaitGroup
+
+		wg.Go(func() {
+
+			counter.WaitUntil(2)
+		})
+
+		requireEventually(t, func() bool {
+
+			return counter.NumWaiters() == 1
+		})
+
+		// Changed() registrations are not blocked goroutines
+		_ = counter.Changed(0)
+
+		require.Equal(t, 1, counter.NumWaiters())
+
+		counter.Step()
+		counter.Step()
+
+		wg.Wait()
+
+		require.Equal(t, 0, counter.NumWaiters())
+	})
+
+	t.Run("AnyLatch and Group NumWaiters()", func(t *testing.T) {
+
+		latch := NewBoolLatch()
+
+		composite := AnyOf(&latch)
+
+		var g Group
+
+		g.Go(func() error {
+
+			composite.Wait()
+
+			return nil
+		})
+
+		var wg sync.WaitGroup
+
+		wg.Go(func() {
+
+			_ = g.Wait()
+		})
+
+		requireEventually(t, func() bool {
+
+			return composite.NumWaiters() == 1 && g.NumWaiters() == 1
+		})
+
+		latch.Set()
+
+		wg.Wait()
+
+		require.Equal(t, 0, composite.NumWaiters())
+		require.Equal(t, 0, g.NumWaiters())
+	})
+
+	t.Run("RingBuffer NumWaiters() and profile", func(t *testing.T) {
+
+		profile := pprof.Lookup(WaitersProfileName)
+		before := profile.Count()
+
+		r := NewRingBuffer[int](2)
+
+		var wg sync.WaitGroup
+
+		for range 2 {
+
+			wg.Go(func() {
+
+				_, _ = r.Pop(context.Background())
+			})
+		}
+
+		requireEventually(t, func() bool {
+
+			return r.NumWaiters() == 2
+		})
+
+		require.Equal(t, before+2, profile.Count())
+
+		require.Nil(t, r.Push(context.Background(), 1))
+		require.Nil(t, r.Push(context.Background(), 2))
+
+		wg.Wait()
+
+		require.Equal(t, 0, r.NumWaiters())
+		require.Equal(t, before, profile.Count())
+
+		require.True(t, r.TryPush(3))
+		require.True(t, r.TryPush(4))
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		wg.Go(func() {
+
+			_ = r.Push(ctx, 5)
+		})
+
+		requireEventually(t, func() bool {
+
+			return r.NumWaiters() == 1
+		})
+
+		cancel()
+
+		wg.Wait()
+
+		require.Equal(t, 0, r.NumWaiters())
+		require.Equal(t, before, profile.Count())
+	})
+}
@@ -0,0 +1,225 @@
+// Copyright 2019-2026 Matthew Wilson and Synesis Information Systems. All
+// rights reserved. Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+ * Created: 19th October 2026
+ * Updated:
+ */
+
+// Definition of a sliding-window counter.
+
+package sync
+
+import (
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	errWindowCounterNumBucketsMustBePositive  = errors.New("number of buckets must be positive")
+	errWindowCounterBucketWidthMustBePositive = errors.New("bucket width must be positive")
+)
+
+type _windowBucket struct {
+	stamp int64 // 1-based index of the period the bucket holds, negated while it is being recycled; 0 if unused
+	count int64
+}
+
+// A counter that, in addition to its lifetime total, records counts in a
+// ring of fixed-width time buckets, allowing the sum and rate over a recent
+// window (of up to the ring's span) to be obtained. It may be operated
+// safely by multiple concurrent goroutines.
+//
+// Step() and Add() take no locks: a bucket that has fallen out of the ring
+// is recycled by whichever incrementer first reaches it in a new period,
+// while others in that period add to it without waiting; only one reaching
+// it in a still later period - which requires the recycling to have stalled
+// for the width of a bucket - yields until the recycling is complete. A
+// count that races with such a rotation may be attributed to the adjacent
+// period, and a bucket is omitted from Sum() while it is being recycled, so
+// counts being added to it may briefly be missed.
+type WindowCounter struct {
+	total   _baseCounter
+	buckets []_windowBucket
+	width   time.Duration
+	clock   Clock
+	origin  time.Time
+}
+
+// Creates a new WindowCounter with numBuckets buckets, each of width
+// bucketWidth, whose times are obtained from clock; if clock is nil, the
+// SystemClock is used.
+//
+// Preconditions:
+// - numBuckets > 0;
+// - bucketWidth > 0;
+func NewWindowCounter(numBuckets int, bucketWidth time.Duration, clock Clock) *WindowCounter {
+
+	if numBuckets <= 0 {
+
+		panic(errWindowCounterNumBucketsMustBePositive)
+	}
+
+	if bucketWidth <= 0 {
+
+		panic(errWindowCounterBucketWidthMustBePositive)
+	}
+
+	clock = clockOrSystem(clock)
+
+	return &WindowCounter{
+		buckets: make([]_windowBucket, numBuckets),
+		width:   bucketWidth,
+		clock:   clock,
+		origin:  clock.Now(),
+	}
+}
+
+// Obtains the 1-based index of the period containing the current time.
+func (c *WindowCounter) period() int64 {
+
+	elapsed := c.clock.Now().Sub(c.origin)
+
+	if elapsed < 0 {
+
+		elapsed = 0
+	}
+
+	return 1 + int64(elapsed/c.width)
+}
+
+// Increments the counter by one.
+//
+// Returns:
+// the new lifetime total
+func (c *WindowCounter) Step() (count int64) {
+
+	count = c.Add(1)
+
+	return
+}
+
+// Increments the counter by n.
+//
+// Returns:
+// the new lifetime total
+func (c *WindowCounter) Add(n int64) (count int64) {
+
+	period := c.period()
+
+	b := &c.buckets[period%int64(len(c.buckets))]
+
+	for {
+
+		stamp := atomic.LoadInt64(&b.stamp)
+
+		// the bucket holds this period, or a later one, or is being recycled
+		// into one of them
+		if stamp >= period || -stamp >= period {
+
+			atomic.AddInt64(&b.count, n)
+
+			break
+		}
+
+		if stamp < 0 {
+
+			// another incrementer is recycling the bucket into an earlier
+			// period
+			runtime.Gosched()
+
+			continue
+		}
+
+		// the bucket holds an expired period: the first incrementer to claim
+		// it marks it as being recycled, discards the expired count, and then
+		// publishes the new period; the others retry and then add
+		stale := atomic.LoadInt64(&b.count)
+
+		if atomic.CompareAndSwapInt64(&b.stamp, stamp, -period) {
+
+			atomic.AddInt64(&b.count, n-stale)
+
+			atomic.StoreInt64(&b.stamp, period)
+
+			break
+		}
+	}
+
+	count = c.total.step(n)
+
+	return
+}
+
+// Obtains the lifetime total of the counter.
+func (c *WindowCounter) Load() (count int64) {
+
+	count = c.total.load()
+
+	return
+}
+
+// Obtains the sum of the counts recorded in the given window, rounded up to
+// a whole number of buckets (and limited to the span of the ring), and
+// including the current, partial, bucket. A bucket that is being recycled
+// is omitted, since its count may still include that of the expired period.
+func (c *WindowCounter) Sum(window time.Duration) (sum int64) {
+
+	n := c.numBucketsFor(window)
+	period := c.period()
+
+	for i := range c.buckets {
+
+		b := &c.buckets[i]
+
+		stamp := atomic.LoadInt64(&b.stamp)
+		count := atomic.LoadInt64(&b.count)
+
+		if stamp > 0 && stamp > period-n && stamp <= period && stamp == atomic.LoadInt64(&b.stamp) {
+
+			sum += count
+		}
+	}
+
+	return
+}
+
+// Obtains the average rate, per second, of the counts recorded in the given
+// window, as Sum(window) divided by the duration of the buckets it spans.
+func (c *WindowCounter) Rate(window time.Duration) float64 {
+
+	n := c.numBucketsFor(window)
+
+	if n == 0 {
+
+		return 0
+	}
+
+	span := time.Duration(n) * c.width
+
+	return float64(c.Sum(window)) / span.Seconds()
+}
+
+// Obtains the total span of time covered by the ring of buckets.
+func (c *WindowCounter) Span() time.Duration {
+
+	return time.Duration(len(c.buckets)) * c.width
+}
+
+func (c *WindowCounter) numBucketsFor(window time.Duration) int64 {
+
+	if window <= 0 {
+
+		return 0
+	}
+
+	if window >= c.Span() {
+
+		return int64(len(c.buckets))
+	}
+
+	return int64((window + c.width - 1) / c.width)
+}
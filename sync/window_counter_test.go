@@ -0,0 +1,203 @@
+package sync_test
+
+import (
+	. "github.com/synesissoftware/syngo/sync"
+
+	"github.com/synesissoftware/syngo/sync/synctesting"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_WindowCounter(t *testing.T) {
+
+	t.Run("NewWindowCounter() panics on invalid arguments", func(t *testing.T) {
+
+		require.Panics(t, func() {
+
+			NewWindowCounter(0, time.Second, nil)
+		})
+
+		require.Panics(t, func() {
+
+			NewWindowCounter(60, 0, nil)
+		})
+	})
+
+	t.Run("Step() and Add() update the lifetime total", func(t *testing.T) {
+
+		counter := NewWindowCounter(60, time.Second, nil)
+
+		require.Equal(t, int64(0), counter.Load())
+		require.Equal(t, int64(1), counter.Step())
+		require.Equal(t, int64(11), counter.Add(10))
+		require.Equal(t, int64(11), counter.Load())
+		require.Equal(t, int64(11), counter.Sum(time.Minute))
+	})
+
+	t.Run("Sum() and Rate() over a sliding window", func(t *testing.T) {
+
+		clock := &synctesting.FakeClock{}
+
+		counter := NewWindowCounter(60, time.Second, clock)
+
+		require.Equal(t, time.Minute, counter.Span())
+
+		for i := 0; i != 10; i++ {
+
+			counter.Add(int64(i + 1))
+
+			clock.Advance(time.Second)
+		}
+
+		// buckets hold 1, 2, ..., 10; the current bucket is empty
+		require.Equal(t, int64(55), counter.Load())
+		require.Equal(t, int64(0), counter.Sum(time.Second))
+		require.Equal(t, int64(10), counter.Sum(2*time.Second))
+		require.Equal(t, int64(19), counter.Sum(3*time.Second))
+		require.Equal(t, int64(55), counter.Sum(time.Minute))
+		require.Equal(t, int64(55), counter.Sum(time.Hour))
+
+		assert.InDelta(t, 19.0/3.0, counter.Rate(3*time.Second), 1e-9)
+		assert.InDelta(t, 55.0/60.0, counter.Rate(time.Minute), 1e-9)
+		assert.Equal(t, 0.0, counter.Rate(0))
+	})
+
+	t.Run("partial windows are rounded up to whole buckets", func(t *testing.T) {
+
+		clock := &synctesting.FakeClock{}
+
+		counter := NewWindowCounter(10, 100*time.Millisecond, clock)
+
+		counter.Add(3)
+
+		clock.Advance(100 * time.Millisecond)
+
+		counter.Add(4)
+
+		require.Equal(t, int64(4), counter.Sum(time.Millisecond))
+		require.Equal(t, int64(7), counter.Sum(101*time.Millisecond))
+	})
+
+	t.Run("expired buckets are recycled", func(t *testing.T) {
+
+		clock := &synctesting.FakeClock{}
+
+		counter := NewWindowCounter(4, time.Second, clock)
+
+		counter.Add(100)
+
+		clock.Advance(4 * time.Second)
+
+		// same slot, new period
+		counter.Add(1)
+
+		require.Equal(t, int64(1), counter.Sum(4*time.Second))
+		require.Equal(t, int64(101), counter.Load())
+
+		clock.Advance(10 * time.Second)
+
+		require.Equal(t, int64(0), counter.Sum(4*time.Second))
+	})
+
+	t.Run("hitting Add() from many goroutines while the clock advances", func(t *testing.T) {
+
+		clock := &synctesting.FakeClock{}
+
+		counter := NewWindowCounter(8, time.Second, clock)
+
+		const numGoroutines = 10
+		const numSteps = 10_000
+
+		var wg sync.WaitGroup
+
+		for i := 0; i != numGoroutines; i++ {
+
+			wg.Go(func() {
+
+				for j := 0; j != numSteps; j++ {
+
+					counter.Step()
+				}
+			})
+		}
+
+		wg.Go(func() {
+
+			for i := 0; i != 4; i++ {
+
+				clock.Advance(time.Second)
+
+				time.Sleep(time.Millisecond)
+			}
+		})
+
+		wg.Wait()
+
+		require.Equal(t, int64(numGoroutines*numSteps), counter.Load())
+		require.Equal(t, int64(numGoroutines*numSteps), counter.Sum(8*time.Second))
+	})
+
+	t.Run("Sum() never includes the expired count of a bucket being recycled", func(t *testing.T) {
+
+		const numRounds = 200
+		const numGoroutines = 4
+		const expired = 1_000_000
+
+		clock := &synctesting.FakeClock{}
+
+		counter := NewWindowCounter(1, time.Second, clock)
+
+		counter.Add(expired)
+
+		var numOvercounts int64
+
+		for range numRounds {
+
+			clock.Advance(time.Second)
+
+			var stop atomic.Bool
+			var sampler, wg sync.WaitGroup
+
+			sampler.Go(func() {
+
+				for !stop.Load() {
+
+					if counter.Sum(time.Second) > numGoroutines {
+
+						atomic.AddInt64(&numOvercounts, 1)
+					}
+
+					runtime.Gosched()
+				}
+			})
+
+			for range numGoroutines {
+
+				wg.Go(func() {
+
+					counter.Step()
+				})
+			}
+
+			wg.Wait()
+
+			stop.Store(true)
+
+			sampler.Wait()
+
+			require.Equal(t, int64(numGoroutines), counter.Sum(time.Second))
+
+			// becomes the expired count of the next round
+			counter.Add(expired)
+		}
+
+		require.Equal(t, int64(0), numOvercounts)
+	})
+}